@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"evgenykuznetsov.org/go/indieweb-glue/internal/strs"
 	"github.com/memcachier/mc/v3"
 )
 
@@ -115,7 +116,7 @@ func canCache(h http.Header) (bool, time.Time) {
 		return true, time.Now().Add(time.Hour)
 	}
 
-	if containsStr(c, "private") || containsStr(c, "no-cache") {
+	if strs.Contains(c, "private") || strs.Contains(c, "no-cache") {
 		return false, time.Unix(0, 0)
 	}
 
@@ -136,7 +137,7 @@ func canCache(h http.Header) (bool, time.Time) {
 		return true, exp
 	}
 
-	if containsStr(c, "public") {
+	if strs.Contains(c, "public") {
 		// CC is "public", but no expiration instructions are given
 		return true, time.Now().Add(time.Hour)
 	}
@@ -5,10 +5,11 @@ go 1.18
 require (
 	github.com/PuerkitoBio/goquery v1.8.1
 	github.com/memcachier/mc/v3 v3.0.3
+	golang.org/x/net v0.7.0
 	willnorris.com/go/microformats v1.2.0
 )
 
 require (
 	github.com/andybalholm/cascadia v1.3.1 // indirect
-	golang.org/x/net v0.7.0 // indirect
+	golang.org/x/text v0.7.0 // indirect
 )
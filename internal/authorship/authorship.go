@@ -0,0 +1,164 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package authorship implements the IndieWeb authorship algorithm, see
+// https://indieweb.org/authorship-spec
+package authorship
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hentry"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+	mf "willnorris.com/go/microformats"
+)
+
+// Steps of the authorship algorithm that can produce the author
+const (
+	StepEntry     = "entry-author"
+	StepFeed      = "feed-author"
+	StepRelAuthor = "rel-author"
+)
+
+// Author represents the author of a post, together with the step of the
+// authorship algorithm that found it
+type Author struct {
+	hcard.HCard
+	Step string `json:"step"`
+}
+
+// Fetch returns the author of the primary h-entry found at the given URL,
+// together with the response header.
+func Fetch(link string) (*Author, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, err := FromDocument(d, res.Request.URL)
+	if err != nil {
+		return nil, &res.Header, err
+	}
+
+	return a, &res.Header, nil
+}
+
+// FromDocument returns the author of the primary h-entry of a document
+// retrieved from the given URL. The author page is fetched if needed.
+func FromDocument(d *goquery.Document, u *url.URL) (*Author, error) {
+	data := mf.ParseNode(d.Get(0), u)
+	entry := hentry.Primary(data, u)
+	if entry == nil {
+		return nil, fmt.Errorf("no h-entry found")
+	}
+
+	step := StepEntry
+	prop, ok := entry.Properties["author"]
+	if !ok || len(prop) == 0 {
+		if feed := parentFeed(data.Items, entry); feed != nil && len(feed.Properties["author"]) > 0 {
+			step = StepFeed
+			prop = feed.Properties["author"]
+		}
+	}
+
+	var authorPage string
+	if len(prop) > 0 {
+		if hc, ok := prop[0].(*mf.Microformat); ok && mf2.HasType(hc, "h-card") {
+			return fromHcard(hc, u, step), nil
+		}
+
+		v := mf2.Value(prop[0])
+		if !isHTTP(v) {
+			return &Author{HCard: hcard.HCard{Source: u.String(), PName: v}, Step: step}, nil
+		}
+		authorPage = v
+	}
+
+	if authorPage == "" && len(mf2.Find(data.Items, "h-entry")) == 1 {
+		if rr := data.Rels["author"]; len(rr) > 0 {
+			step = StepRelAuthor
+			authorPage = rr[0]
+		}
+	}
+
+	if authorPage == "" {
+		return nil, fmt.Errorf("no author found")
+	}
+
+	if hc, _, err := hcard.Fetch(authorPage); err == nil {
+		return &Author{HCard: *hc, Step: step}, nil
+	}
+
+	for _, hc := range mf2.Find(data.Items, "h-card") {
+		for _, link := range mf2.Properties(hc, "url") {
+			if urlnorm.Equivalent(link, authorPage) {
+				return fromHcard(hc, u, step), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no h-card found for author page %s", authorPage)
+}
+
+func fromHcard(i *mf.Microformat, u *url.URL, step string) *Author {
+	hc := hcard.FromMicroformat(i)
+	hc.Source = u.String()
+	return &Author{HCard: *hc, Step: step}
+}
+
+// parentFeed returns the h-feed that has the entry as its child
+func parentFeed(items []*mf.Microformat, entry *mf.Microformat) *mf.Microformat {
+	for _, i := range items {
+		if mf2.HasType(i, "h-feed") {
+			for _, c := range i.Children {
+				if c == entry {
+					return i
+				}
+			}
+		}
+		if f := parentFeed(i.Children, entry); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func isHTTP(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package authorship
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	tests := map[string]struct {
+		link   string
+		name   string
+		note   string
+		step   string
+		source string
+	}{
+		"embedded h-card": {"/entry.html", "Jane Doe", "", StepEntry, "/entry.html"},
+		"feed author":     {"/feed.html", "Feed Owner", "", StepFeed, "/feed.html"},
+		"plain name":      {"/name.html", "Anonymous Coward", "", StepEntry, "/name.html"},
+		"author URL":      {"/url.html", "Jane Doe", "I write things.", StepEntry, "/about.html"},
+		"rel=author":      {"/relauthor.html", "Jane Doe", "I write things.", StepRelAuthor, "/about.html"},
+		"card on page":    {"/onpage.html", "Page Owner", "", StepEntry, "/onpage.html"},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, _, err := Fetch(s.URL + tc.link)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if a.PName != tc.name {
+				t.Fatalf("want name %q, got %q", tc.name, a.PName)
+			}
+			if a.Note != tc.note {
+				t.Fatalf("want note %q, got %q", tc.note, a.Note)
+			}
+			if a.Step != tc.step {
+				t.Fatalf("want step %q, got %q", tc.step, a.Step)
+			}
+			if a.Source != s.URL+tc.source {
+				t.Fatalf("want source %q, got %q", s.URL+tc.source, a.Source)
+			}
+		})
+	}
+}
+
+func TestNoAuthor(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for _, link := range []string{"/nocard.html", "/about.html"} {
+		if _, _, err := Fetch(s.URL + link); err == nil {
+			t.Fatalf("%s: want error, got none", link)
+		}
+	}
+}
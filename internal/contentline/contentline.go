@@ -0,0 +1,53 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package contentline provides the content line handling shared by
+// iCalendar (RFC 5545) and vCard (RFC 6350).
+package contentline
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLength is the maximum length of a content line in octets
+const MaxLength = 75
+
+// EscapeText escapes a text value, see RFC 5545 section 3.3.11 and RFC 6350
+// section 3.4
+func EscapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// EscapeParam escapes a quoted parameter value, see RFC 6868
+func EscapeParam(s string) string {
+	return strings.NewReplacer("^", "^^", "\r\n", "^n", "\n", "^n", `"`, "^'").Replace(s)
+}
+
+// Write writes the content line folded as per RFC 5545 section 3.1 and
+// RFC 6350 section 3.2, never splitting a multi-octet character
+func Write(b *strings.Builder, line string) {
+	limit := MaxLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i] + "\r\n ")
+		line = line[i:]
+		limit = MaxLength - 1
+	}
+	b.WriteString(line + "\r\n")
+}
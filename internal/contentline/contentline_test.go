@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package contentline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := map[string]string{
+		"short":       "SUMMARY:Hello",
+		"ascii":       "SUMMARY:" + strings.Repeat("a", 200),
+		"multi-octet": "NOTE:" + strings.Repeat("Ж", 100),
+	}
+
+	for name, line := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+			Write(&b, line)
+
+			got := b.String()
+			if !strings.HasSuffix(got, "\r\n") {
+				t.Fatalf("want %q to end with CRLF", got)
+			}
+			var unfolded strings.Builder
+			for n, l := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+				if len(l) > MaxLength {
+					t.Fatalf("line longer than %d octets: %q", MaxLength, l)
+				}
+				if n > 0 {
+					if !strings.HasPrefix(l, " ") {
+						t.Fatalf("continuation line without leading space: %q", l)
+					}
+					l = l[1:]
+				}
+				unfolded.WriteString(l)
+			}
+			if unfolded.String() != line {
+				t.Fatalf("want %q, got %q", line, unfolded.String())
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got, want := EscapeText("a\\b, c; d\r\ne\nf"), `a\\b\, c\; d\ne\nf`; got != want {
+		t.Fatalf("want text %q, got %q", want, got)
+	}
+	if got, want := EscapeParam("say \"hi\"^\nbye"), "say ^'hi^'^^^nbye"; got != want {
+		t.Fatalf("want param %q, got %q", want, got)
+	}
+}
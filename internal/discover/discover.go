@@ -0,0 +1,208 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package discover provides discovery of the IndieWeb endpoints (Webmention,
+// Micropub, Microsub, IndieAuth, WebSub) advertised by a page.
+package discover
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/strs"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Endpoints represents the endpoints advertised by a page
+type Endpoints struct {
+	Source                string   `json:"source,omitempty"`
+	Webmention            string   `json:"webmention,omitempty"`
+	Micropub              string   `json:"micropub,omitempty"`
+	Microsub              string   `json:"microsub,omitempty"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint         string   `json:"token_endpoint,omitempty"`
+	IndieAuthMetadata     string   `json:"indieauth-metadata,omitempty"`
+	Hub                   []string `json:"hub,omitempty"`
+	Self                  string   `json:"self,omitempty"`
+}
+
+// Link represents a link with its relations
+type Link struct {
+	URL  string
+	Rels []string
+}
+
+// legacyWebmention is the rel value used by early Webmention implementations
+const legacyWebmention = "http://webmention.org/"
+
+// Fetch fetches the page at URI and returns the endpoints it advertises,
+// together with the response header.
+func Fetch(uri string) (*Endpoints, *http.Header, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	links := ParseLinkHeader(res.Header.Values("Link"), res.Request.URL)
+
+	if ct := res.Header.Get("Content-Type"); ct == "" || strings.Contains(ct, "html") {
+		d, err := goquery.NewDocumentFromReader(res.Body)
+		if err == nil {
+			links = append(links, FromDocument(d, res.Request.URL)...)
+		}
+	}
+
+	e := FromLinks(links)
+	if reflect.DeepEqual(e, Endpoints{}) {
+		return nil, &res.Header, fmt.Errorf("no endpoints found")
+	}
+	e.Source = res.Request.URL.String()
+
+	return &e, &res.Header, nil
+}
+
+// FromLinks returns the endpoints found among links. The first link with a
+// given relation wins, so the links from the HTTP headers should come before
+// the ones from the document. All the hubs are returned.
+func FromLinks(links []Link) Endpoints {
+	var e Endpoints
+	first := map[string]*string{
+		"webmention":             &e.Webmention,
+		legacyWebmention:         &e.Webmention,
+		"micropub":               &e.Micropub,
+		"microsub":               &e.Microsub,
+		"authorization_endpoint": &e.AuthorizationEndpoint,
+		"token_endpoint":         &e.TokenEndpoint,
+		"indieauth-metadata":     &e.IndieAuthMetadata,
+		"self":                   &e.Self,
+	}
+
+	for _, l := range links {
+		for _, rel := range l.Rels {
+			if rel == "hub" {
+				if !strs.Contains(e.Hub, l.URL) {
+					e.Hub = append(e.Hub, l.URL)
+				}
+				continue
+			}
+			if v, ok := first[rel]; ok && *v == "" {
+				*v = l.URL
+			}
+		}
+	}
+
+	return e
+}
+
+// FromDocument returns the links with relations from the <link> and <a>
+// elements of a document retrieved from the given URL, in document order.
+// Relative URLs are resolved taking <base> into account.
+func FromDocument(d *goquery.Document, u *url.URL) (links []Link) {
+	base := urlnorm.Base(d, u)
+
+	d.Find("link[rel], a[rel]").Each(func(_ int, s *goquery.Selection) {
+		href, ok := s.Attr("href")
+		if !ok {
+			return
+		}
+		rel, _ := s.Attr("rel")
+		l, err := base.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		links = append(links, Link{URL: l.String(), Rels: splitRels(rel)})
+	})
+	return
+}
+
+// ParseLinkHeader returns the links found in the values of HTTP Link
+// headers, with relative URLs resolved against the given URL.
+func ParseLinkHeader(values []string, u *url.URL) (links []Link) {
+	for _, v := range values {
+		for _, part := range splitOutside(v, ',') {
+			part = strings.TrimSpace(part)
+			if !strings.HasPrefix(part, "<") {
+				continue
+			}
+			end := strings.Index(part, ">")
+			if end < 0 {
+				continue
+			}
+
+			l, err := u.Parse(strings.TrimSpace(part[1:end]))
+			if err != nil {
+				continue
+			}
+
+			var rels []string
+			for _, param := range splitOutside(part[end+1:], ';') {
+				name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				rels = append(rels, splitRels(strings.Trim(strings.TrimSpace(value), `"`))...)
+			}
+
+			if len(rels) > 0 {
+				links = append(links, Link{URL: l.String(), Rels: rels})
+			}
+		}
+	}
+	return
+}
+
+// splitOutside splits s by sep, ignoring the separators enclosed in angle
+// brackets or double quotes
+func splitOutside(s string, sep rune) (parts []string) {
+	var inBrackets, inQuotes bool
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"' && !inBrackets:
+			inQuotes = !inQuotes
+		case r == '<' && !inQuotes:
+			inBrackets = true
+		case r == '>' && !inQuotes:
+			inBrackets = false
+		case r == sep && !inBrackets && !inQuotes:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// splitRels returns the lowercase relations from a rel attribute value
+func splitRels(rel string) []string {
+	rels := strings.Fields(rel)
+	for i, r := range rels {
+		rels[i] = strings.ToLower(r)
+	}
+	return rels
+}
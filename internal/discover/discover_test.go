@@ -0,0 +1,138 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	pages := map[string]struct {
+		link string
+		body string
+	}{
+		"/header":   {`</wm/header>; rel="webmention"`, `<link rel="webmention" href="/wm/html">`},
+		"/quoted":   {`<https://example.com/a,b>; rel="other webmention", </mp>; rel=micropub`, ``},
+		"/relative": {``, `<link rel="webmention" href="wm?x=1">`},
+		"/empty":    {``, `<link rel="webmention" href="">`},
+		"/order":    {``, `<a rel="webmention" href="/wm/a">a</a><link rel="webmention" href="/wm/link">`},
+		"/comment":  {``, `<!-- <link rel="webmention" href="/wm/comment"> --><link rel="webmention" href="/wm/real">`},
+		"/multi":    {``, `<link rel="nofollow Webmention" href="/wm/multi">`},
+		"/legacy":   {``, `<link rel="http://webmention.org/" href="/wm/legacy">`},
+		"/base":     {``, `<base href="/other/"><link rel="webmention" href="wm">`},
+		"/dir/page": {``, `<link rel="webmention" href="../wm"><link rel="micropub" href="/micropub"><link rel="microsub" href="/microsub">` +
+			`<link rel="authorization_endpoint" href="/auth"><link rel="token_endpoint" href="/token">` +
+			`<link rel="indieauth-metadata" href="/.well-known/oauth-authorization-server">` +
+			`<link rel="hub" href="https://hub.example/"><link rel="hub" href="https://hub2.example/"><link rel="self" href="/dir/page">`},
+		"/none": {``, `<p>Nothing here.</p>`},
+	}
+
+	mux := http.NewServeMux()
+	for p, page := range pages {
+		page := page
+		mux.HandleFunc(p, func(w http.ResponseWriter, _ *http.Request) {
+			if page.link != "" {
+				w.Header().Set("Link", page.link)
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>test</title></head><body>%s</body></html>", page.body)
+		})
+	}
+	mux.Handle("/redirect", http.RedirectHandler("/dir/page", http.StatusFound))
+
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	tests := map[string]struct {
+		link string
+		want Endpoints
+	}{
+		"header wins":     {"/header", Endpoints{Webmention: "/wm/header"}},
+		"quoted header":   {"/quoted", Endpoints{Webmention: "https://example.com/a,b", Micropub: "/mp"}},
+		"relative":        {"/relative", Endpoints{Webmention: "/wm?x=1"}},
+		"empty href":      {"/empty", Endpoints{Webmention: "/empty"}},
+		"document order":  {"/order", Endpoints{Webmention: "/wm/a"}},
+		"comments ignore": {"/comment", Endpoints{Webmention: "/wm/real"}},
+		"multiple rels":   {"/multi", Endpoints{Webmention: "/wm/multi"}},
+		"legacy rel":      {"/legacy", Endpoints{Webmention: "/wm/legacy"}},
+		"base":            {"/base", Endpoints{Webmention: "/other/wm"}},
+		"redirect": {"/redirect", Endpoints{
+			Source:                "/dir/page",
+			Webmention:            "/wm",
+			Micropub:              "/micropub",
+			Microsub:              "/microsub",
+			AuthorizationEndpoint: "/auth",
+			TokenEndpoint:         "/token",
+			IndieAuthMetadata:     "/.well-known/oauth-authorization-server",
+			Hub:                   []string{"https://hub.example/", "https://hub2.example/"},
+			Self:                  "/dir/page",
+		}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _, err := Fetch(s.URL + tc.link)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			want := absolute(tc.want, s.URL)
+			if want.Source == "" {
+				want.Source = s.URL + tc.link
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Fatalf("want %+v, got %+v", want, *got)
+			}
+		})
+	}
+
+	if _, _, err := Fetch(s.URL + "/none"); err == nil {
+		t.Fatal("want error for a page without endpoints, got none")
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	u, _ := url.Parse("https://example.com/post")
+	got := ParseLinkHeader([]string{
+		`<https://example.com/wm>; rel="webmention"`,
+		`</hub>; rel=hub, <https://example.com/post>; rel="self canonical"; type="text/html"`,
+		`no link here`,
+	}, u)
+	want := []Link{
+		{"https://example.com/wm", []string{"webmention"}},
+		{"https://example.com/hub", []string{"hub"}},
+		{"https://example.com/post", []string{"self", "canonical"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+// absolute prefixes the local paths in e with the server URL
+func absolute(e Endpoints, prefix string) Endpoints {
+	for _, s := range []*string{&e.Source, &e.Webmention, &e.Micropub, &e.Microsub, &e.AuthorizationEndpoint, &e.TokenEndpoint, &e.IndieAuthMetadata, &e.Self} {
+		if len(*s) > 0 && (*s)[0] == '/' {
+			*s = prefix + *s
+		}
+	}
+	return e
+}
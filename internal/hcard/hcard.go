@@ -16,36 +16,95 @@
 package hcard
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
 	"github.com/PuerkitoBio/goquery"
 	mf "willnorris.com/go/microformats"
 )
 
+// The checks of the representative h-card algorithm that may select an
+// h-card, see https://microformats.org/wiki/representative-h-card-parsing
+const (
+	CheckUIDURL   = "uid-url"      // uid and url match the page URL
+	CheckRelMe    = "rel-me"       // url matches a rel=me link of the page
+	CheckSole     = "sole-card"    // the only h-card, url matches the page URL
+	CheckFollowed = "followed-url" // h-card on a followed page, url matches the original page URL
+)
+
+// CheckJSONLD tells that the card is not an h-card, but the schema.org Person
+// representing the page in its JSON-LD
+const CheckJSONLD = "json-ld"
+
 // HCard represents a h-card
 type HCard struct {
-	Source   string `json:"source,omitempty"`
-	PName    string `json:"pname,omitempty"`
-	Nickname string `json:"nickname,omitempty"`
-	Note     string `json:"note,omitempty"`
-	Photo    string `json:"uphoto,omitempty"`
+	Source      string   `json:"source,omitempty"`
+	Check       string   `json:"check,omitempty"`
+	PName       string   `json:"pname,omitempty"`
+	Nickname    string   `json:"nickname,omitempty"`
+	Note        string   `json:"note,omitempty"`
+	Photo       string   `json:"uphoto,omitempty"`
+	Photos      []Photo  `json:"photo,omitempty"`
+	URL         []string `json:"url,omitempty"`
+	UID         []string `json:"uid,omitempty"`
+	Email       []string `json:"email,omitempty"`
+	Tel         []string `json:"tel,omitempty"`
+	Org         []*HCard `json:"org,omitempty"`
+	JobTitle    []string `json:"jobTitle,omitempty"`
+	Locality    string   `json:"locality,omitempty"`
+	Region      string   `json:"region,omitempty"`
+	CountryName string   `json:"countryName,omitempty"`
+	Adr         []Adr    `json:"adr,omitempty"`
+	Bday        string   `json:"bday,omitempty"`
+	Pronouns    []string `json:"pronouns,omitempty"`
+	Category    []string `json:"category,omitempty"`
+	Key         []string `json:"key,omitempty"`
 }
 
-func getRepresentativeHcard(page io.Reader, url *url.URL) (m *mf.Microformat) {
-	doc, err := goquery.NewDocumentFromReader(page)
-	if err != nil {
-		return
-	}
+// ContextTopLevel is the context of an h-card that is not nested in any other
+// microformat
+const ContextTopLevel = "top-level"
+
+// Card represents an h-card found on a page together with the context it was
+// found in: either ContextTopLevel, or the dot-separated path of properties it
+// was nested under (e.g. "author.org"); h-cards nested without a property are
+// denoted by "children".
+type Card struct {
+	HCard
+	Context string `json:"context"`
+}
 
+// Photo represents a photo of a h-card
+type Photo struct {
+	Value string `json:"value"`
+	Alt   string `json:"alt,omitempty"`
+}
+
+// Adr represents a h-adr
+type Adr struct {
+	Label         string `json:"label,omitempty"`
+	StreetAddress string `json:"streetAddress,omitempty"`
+	Locality      string `json:"locality,omitempty"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postalCode,omitempty"`
+	CountryName   string `json:"countryName,omitempty"`
+}
+
+// getRepresentativeHcard returns the representative h-card of the document
+// together with the check that selected it.
+func getRepresentativeHcard(doc *goquery.Document, url *url.URL) (m *mf.Microformat, check string) {
 	hcards := getHcards(doc, url)
 
 	// check 1 (first h-card where uid == url == page URL)
 	for _, hc := range hcards {
 		if matchUrlUid(hc, url) {
-			return hc
+			return hc, CheckUIDURL
 		}
 	}
 
@@ -54,8 +113,8 @@ func getRepresentativeHcard(page io.Reader, url *url.URL) (m *mf.Microformat) {
 	if mm, ok := d.Rels["me"]; ok {
 		for _, hc := range hcards {
 			for _, me := range mm {
-				if matchURLs(parseProperty(hc, "url"), me) {
-					return hc
+				if matchAny(mf2.Properties(hc, "url"), me) {
+					return hc, CheckRelMe
 				}
 			}
 		}
@@ -63,8 +122,8 @@ func getRepresentativeHcard(page io.Reader, url *url.URL) (m *mf.Microformat) {
 
 	// check 3 (single h-card and url == page URL)
 	if len(hcards) == 1 {
-		if matchURLs(parseProperty(hcards[0], "url"), url.String()) {
-			return hcards[0]
+		if matchAny(mf2.Properties(hcards[0], "url"), url.String()) {
+			return hcards[0], CheckSole
 		}
 	}
 
@@ -91,9 +150,66 @@ func getHcards(doc *goquery.Document, u *url.URL) (hcards []*mf.Microformat) {
 // Fetch returns the representative H-Card found at the given URL, together
 // with the response header.
 func Fetch(link string) (*HCard, *http.Header, error) {
+	return FetchFollowing(link, 0)
+}
+
+// FetchFollowing returns the representative H-Card found at the given URL,
+// together with the response header. If there is none, up to hops rel=author
+// and rel=me links to the pages of the same origin are followed, and the
+// h-card is looked for there; the Source of the HCard is the page it was
+// found on.
+func FetchFollowing(link string, hops int) (*HCard, *http.Header, error) {
+	doc, origin, hd, err := FetchPage(link)
+	if err != nil {
+		return nil, hd, err
+	}
+
+	hc, err := FromDocumentFollowing(doc, origin, hops)
+	return hc, hd, err
+}
+
+// FromDocumentFollowing returns the representative H-Card of a document
+// retrieved from the given URL. If there is none, up to hops rel=author and
+// rel=me links to the pages of the same origin are followed, as
+// FetchFollowing does.
+func FromDocumentFollowing(doc *goquery.Document, origin *url.URL, hops int) (*HCard, error) {
+	hc, err := FromDocument(doc, origin)
+	if err == nil || hops < 1 {
+		return hc, err
+	}
+
+	visited := map[string]bool{origin.String(): true}
+	queue := sameOriginLinks(doc, origin, origin)
+	for hop := 0; hop < hops && len(queue) > 0; hop++ {
+		var next []string
+		for _, l := range queue {
+			if visited[l] {
+				continue
+			}
+			visited[l] = true
+
+			d, pu, _, err := FetchPage(l)
+			if err != nil || !sameOrigin(pu, origin) {
+				continue
+			}
+
+			if hc := cardFor(d, pu, origin); hc != nil {
+				return hc, nil
+			}
+			next = append(next, sameOriginLinks(d, pu, origin)...)
+		}
+		queue = next
+	}
+
+	return nil, fmt.Errorf("no representative h-card found")
+}
+
+// FetchPage fetches the page at the given URL and returns the document
+// together with its final URL and the response header.
+func FetchPage(link string) (*goquery.Document, *url.URL, *http.Header, error) {
 	u, err := url.Parse(link)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if u.Scheme == "" {
@@ -102,17 +218,132 @@ func Fetch(link string) (*HCard, *http.Header, error) {
 
 	res, err := http.Get(u.String())
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	defer res.Body.Close()
 
-	i := getRepresentativeHcard(res.Body, res.Request.URL)
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, &res.Header, err
+	}
+
+	return doc, res.Request.URL, &res.Header, nil
+}
+
+// cardFor returns the representative H-Card of a followed page, or the first
+// h-card on it with url matching the original page URL.
+func cardFor(doc *goquery.Document, u, origin *url.URL) *HCard {
+	if hc, err := FromDocument(doc, u); err == nil {
+		return hc
+	}
+
+	for _, i := range getHcards(doc, u) {
+		if matchAny(mf2.Properties(i, "url"), origin.String()) {
+			hc := FromMicroformat(i)
+			hc.Source = u.String()
+			hc.Check = CheckFollowed
+			return hc
+		}
+	}
+	return nil
+}
+
+// sameOriginLinks returns the rel=author and rel=me links of a document
+// retrieved from URL u that point to the same origin as the given one.
+func sameOriginLinks(doc *goquery.Document, u, origin *url.URL) (links []string) {
+	d := mf.ParseNode(doc.Get(0), u)
+	for _, rel := range []string{"author", "me"} {
+		for _, l := range d.Rels[rel] {
+			lu, err := url.Parse(l)
+			if err == nil && sameOrigin(lu, origin) {
+				lu.Fragment = ""
+				links = append(links, lu.String())
+			}
+		}
+	}
+	return
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// FetchAll returns every h-card found at the given URL, together with the
+// response header.
+func FetchAll(link string) ([]Card, *http.Header, error) {
+	d, hd, err := mf2.Fetch(link)
+	if err != nil {
+		return nil, hd, err
+	}
+
+	cards := All(d)
+	if len(cards) == 0 {
+		return nil, hd, fmt.Errorf("no h-cards found")
+	}
+	return cards, hd, nil
+}
+
+// All returns every h-card of the parsed microformats data in document order,
+// dropping the duplicates of the h-cards already found.
+func All(d *mf.Data) (cards []Card) {
+	seen := map[string]bool{}
+	var walk func(items []*mf.Microformat, path []string)
+	walk = func(items []*mf.Microformat, path []string) {
+		for _, i := range items {
+			if mf2.HasType(i, "h-card") {
+				c := Card{HCard: *FromMicroformat(i), Context: ContextTopLevel}
+				if len(path) > 0 {
+					c.Context = strings.Join(path, ".")
+				}
+				if key, err := json.Marshal(c.HCard); err == nil && !seen[string(key)] {
+					seen[string(key)] = true
+					cards = append(cards, c)
+				}
+			}
+
+			for _, p := range sortedKeys(i.Properties) {
+				for _, v := range i.Properties[p] {
+					if m, ok := v.(*mf.Microformat); ok {
+						walk([]*mf.Microformat{m}, append(path[:len(path):len(path)], p))
+					}
+				}
+			}
+			walk(i.Children, append(path[:len(path):len(path)], "children"))
+		}
+	}
+	walk(d.Items, nil)
+	return
+}
+
+// sortedKeys returns the property names in a stable order.
+func sortedKeys(props map[string][]interface{}) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FromDocument returns the representative H-Card of a document retrieved
+// from the given URL.
+func FromDocument(doc *goquery.Document, u *url.URL) (*HCard, error) {
+	i, check := getRepresentativeHcard(doc, u)
 	if i == nil {
-		return nil, &res.Header, fmt.Errorf("no representative h-card found")
+		return nil, fmt.Errorf("no representative h-card found")
 	}
 
+	hc := FromMicroformat(i)
+	hc.Source = u.String()
+	hc.Check = check
+
+	return hc, nil
+}
+
+// FromMicroformat returns the HCard described by the parsed h-card
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HCard {
 	var hc HCard
-	hc.Source = res.Request.URL.String()
 
 	for _, t := range i.Type {
 		switch t {
@@ -121,10 +352,95 @@ func Fetch(link string) (*HCard, *http.Header, error) {
 			hc.PName = parseProperty(i, "name")
 			hc.Nickname = parseProperty(i, "nickname")
 			hc.Note = parseProperty(i, "note")
+			hc.Photos = parsePhotos(i)
+			hc.URL = mf2.Properties(i, "url")
+			hc.UID = mf2.Properties(i, "uid")
+			hc.Email = parseEmails(i)
+			hc.Tel = mf2.Properties(i, "tel")
+			hc.Org = parseOrgs(i)
+			hc.JobTitle = mf2.Properties(i, "job-title")
+			hc.Locality = parseProperty(i, "locality")
+			hc.Region = parseProperty(i, "region")
+			hc.CountryName = parseProperty(i, "country-name")
+			hc.Adr = parseAdrs(i)
+			hc.Bday = parseProperty(i, "bday")
+			hc.Pronouns = mf2.Properties(i, "pronouns")
+			hc.Category = mf2.Properties(i, "category")
+			hc.Key = mf2.Properties(i, "key")
+		}
+	}
+
+	return &hc
+}
+
+// FromProperty returns the HCard of the property of the microformat: the
+// nested h-card, just the URL if the property is a URL, or just the name if
+// the property is plain text.
+func FromProperty(m *mf.Microformat, property string) *HCard {
+	if c := mf2.Embedded(m, property); c != nil && mf2.HasType(c, "h-card") {
+		return FromMicroformat(c)
+	}
+	v := strings.TrimSpace(mf2.Property(m, property))
+	if v == "" {
+		return nil
+	}
+	if u, err := url.Parse(v); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return &HCard{URL: []string{v}}
+	}
+	return &HCard{PName: v}
+}
+
+func parsePhotos(m *mf.Microformat) (photos []Photo) {
+	for _, v := range m.Properties["photo"] {
+		p := Photo{Value: mf2.Value(v)}
+		if vv, ok := v.(map[string]string); ok {
+			p.Alt = vv["alt"]
+		}
+		if p.Value != "" {
+			photos = append(photos, p)
 		}
 	}
+	return
+}
 
-	return &hc, &res.Header, nil
+func parseOrgs(m *mf.Microformat) (orgs []*HCard) {
+	for _, v := range m.Properties["org"] {
+		if o, ok := v.(*mf.Microformat); ok && mf2.HasType(o, "h-card") {
+			orgs = append(orgs, FromMicroformat(o))
+			continue
+		}
+		if name := mf2.Value(v); name != "" {
+			orgs = append(orgs, &HCard{PName: name})
+		}
+	}
+	return
+}
+
+func parseAdrs(m *mf.Microformat) (adrs []Adr) {
+	for _, v := range m.Properties["adr"] {
+		a, ok := v.(*mf.Microformat)
+		if !ok || !mf2.HasType(a, "h-adr") {
+			if label := mf2.Value(v); label != "" {
+				adrs = append(adrs, Adr{Label: label})
+			}
+			continue
+		}
+		adrs = append(adrs, AdrFromMicroformat(a))
+	}
+	return
+}
+
+// AdrFromMicroformat returns the Adr described by the parsed h-adr
+// microformat.
+func AdrFromMicroformat(a *mf.Microformat) Adr {
+	return Adr{
+		Label:         parseProperty(a, "label"),
+		StreetAddress: parseProperty(a, "street-address"),
+		Locality:      parseProperty(a, "locality"),
+		Region:        parseProperty(a, "region"),
+		PostalCode:    parseProperty(a, "postal-code"),
+		CountryName:   parseProperty(a, "country-name"),
+	}
 }
 
 func Empty() (*HCard, map[string][]string) {
@@ -133,6 +449,40 @@ func Empty() (*HCard, map[string][]string) {
 	return &h, hd
 }
 
+// parseEmails returns the email addresses of the h-card as mailto: URIs.
+func parseEmails(m *mf.Microformat) (emails []string) {
+	for _, v := range mf2.Properties(m, "email") {
+		if e := email(v); e != "" {
+			emails = append(emails, e)
+		}
+	}
+	return
+}
+
+// email returns the email property value as a mailto: URI: the value of a
+// u-email is a mailto: URI already, the value of a p-email is the plain
+// address. Anything else, e.g. a plain-text u-email the parser resolved as
+// a relative URL, is not an address and the empty string is returned.
+func email(v string) string {
+	u, err := url.Parse(strings.TrimSpace(v))
+	if err != nil {
+		return ""
+	}
+
+	var addr string
+	switch strings.ToLower(u.Scheme) {
+	case "mailto":
+		addr, _ = url.PathUnescape(u.Opaque)
+	case "":
+		addr = u.String()
+	}
+
+	if at := strings.Index(addr, "@"); at < 1 || at == len(addr)-1 {
+		return ""
+	}
+	return "mailto:" + addr
+}
+
 func parseProperty(m *mf.Microformat, property string) (value string) {
 	if len(m.Properties[property]) < 1 {
 		return
@@ -148,33 +498,20 @@ func parseProperty(m *mf.Microformat, property string) (value string) {
 }
 
 func matchUrlUid(hc *mf.Microformat, u *url.URL) bool {
-	uidString := parseProperty(hc, "uid")
-	if uidString == "" {
-		return false
-	}
-
-	urlString := parseProperty(hc, "url")
+	return matchAny(mf2.Properties(hc, "uid"), u.String()) &&
+		matchAny(mf2.Properties(hc, "url"), u.String())
+}
 
-	if matchURLs(uidString, urlString) && matchURLs(urlString, u.String()) {
-		return true
+// matchAny reports whether any of the values matches the URL.
+func matchAny(values []string, u string) bool {
+	for _, v := range values {
+		if matchURLs(v, u) {
+			return true
+		}
 	}
 	return false
 }
 
 func matchURLs(a, b string) bool {
-	aUrl, err := url.Parse(a)
-	if err != nil {
-		return false
-	}
-
-	bUrl, err := url.Parse(b)
-	if err != nil {
-		return false
-	}
-
-	if aUrl.String() != bUrl.String() {
-		return false
-	}
-
-	return true
+	return urlnorm.Equivalent(a, b)
 }
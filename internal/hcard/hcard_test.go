@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -77,3 +78,170 @@ func TestProperties(t *testing.T) {
 		})
 	}
 }
+
+func TestFullModel(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	hc, _, err := Fetch(s.URL + "/full.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	want := HCard{
+		Source:      s.URL + "/full.html",
+		Check:       CheckUIDURL,
+		PName:       "Jane Doe",
+		Note:        "Hi, I'm Jane.",
+		Photo:       s.URL + "/jane.jpg",
+		Photos:      []Photo{{s.URL + "/jane.jpg", "Jane smiling"}, {s.URL + "/jane-old.jpg", ""}},
+		URL:         []string{s.URL + "/full.html", "https://social.example/@jane"},
+		UID:         []string{s.URL + "/full.html"},
+		Email:       []string{"mailto:jane@example.com"},
+		Tel:         []string{"+1 555 555 0100", "+1 555 555 0101"},
+		JobTitle:    []string{"Engineer"},
+		Locality:    "Springfield",
+		Region:      "Oregon",
+		CountryName: "USA",
+		Adr:         []Adr{{StreetAddress: "742 Evergreen Terrace", Locality: "Springfield", PostalCode: "97403"}},
+		Bday:        "1980-05-12",
+		Pronouns:    []string{"she/her"},
+		Category:    []string{"cycling", "photography"},
+		Key:         []string{s.URL + "/key.asc"},
+	}
+
+	if len(hc.Org) != 2 {
+		t.Fatalf("want 2 orgs, got %d", len(hc.Org))
+	}
+	if hc.Org[0].PName != "ACME" || !reflect.DeepEqual(hc.Org[0].URL, []string{"https://acme.example/"}) {
+		t.Fatalf("want ACME h-card org, got %+v", hc.Org[0])
+	}
+	if hc.Org[1].PName != "Volunteer Fire Brigade" {
+		t.Fatalf("want plain text org, got %+v", hc.Org[1])
+	}
+
+	got := *hc
+	got.Org = nil
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestFetchFollowing(t *testing.T) {
+	tests := map[string]struct {
+		hops   int
+		source string
+	}{
+		"no hops":    {0, ""},
+		"one hop":    {1, ""},
+		"two hops":   {2, "/follow/card.html"},
+		"three hops": {3, "/follow/card.html"},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			hc, _, err := FetchFollowing(s.URL+"/follow/", tc.hops)
+			if tc.source == "" {
+				if err == nil {
+					t.Fatalf("want error, got h-card from %s", hc.Source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if hc.Source != s.URL+tc.source {
+				t.Fatalf("want source %s, got %s", s.URL+tc.source, hc.Source)
+			}
+			if hc.Note != "Found it." {
+				t.Fatalf("want note %q, got %q", "Found it.", hc.Note)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	tests := map[string]struct {
+		link  string
+		name  string
+		check string
+	}{
+		"uid and url": {"/checks/uid.html", "Uid", CheckUIDURL},
+		"rel=me":      {"/checks/relme.html", "Relme", CheckRelMe},
+		"sole card":   {"/checks/sole.html", "Sole", CheckSole},
+		"followed":    {"/follow/", "Jane Doe", CheckFollowed},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			hc, _, err := FetchFollowing(s.URL+tc.link, 2)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if hc.PName != tc.name || hc.Check != tc.check {
+				t.Fatalf("want %s selected by %s, got %s selected by %s", tc.name, tc.check, hc.PName, hc.Check)
+			}
+		})
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	cards, _, err := FetchAll(s.URL + "/team.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	want := []struct{ name, context string }{
+		{"Alice", ContextTopLevel},
+		{"ACME", "org"},
+		{"Bob", "author"},
+		{"Carol", "children"},
+	}
+	if len(cards) != len(want) {
+		t.Fatalf("want %d h-cards, got %+v", len(want), cards)
+	}
+	for i, w := range want {
+		if cards[i].PName != w.name || cards[i].Context != w.context {
+			t.Fatalf("want %s in %s context, got %s in %s context", w.name, w.context, cards[i].PName, cards[i].Context)
+		}
+	}
+}
+
+func TestEmail(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		want  string
+	}{
+		"mailto":        {"mailto:jane@example.com", "mailto:jane@example.com"},
+		"upper case":    {"MAILTO:jane@example.com", "mailto:jane@example.com"},
+		"mailto query":  {"mailto:jane@example.com?subject=Hi", "mailto:jane@example.com"},
+		"plain text":    {"jane@example.com", "mailto:jane@example.com"},
+		"resolved":      {"https://example.com/about/jane@example.com", ""},
+		"not an email":  {"https://example.com/about/", ""},
+		"mastodon link": {"https://social.example/@jane", ""},
+		"empty mailto":  {"mailto:", ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := email(tc.value); got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package hentry provides handling for h-entry microformats.
+package hentry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/posttype"
+	"github.com/PuerkitoBio/goquery"
+	mf "willnorris.com/go/microformats"
+)
+
+// HEntry represents a h-entry
+type HEntry struct {
+	Source    string       `json:"source,omitempty"`
+	Name      string       `json:"name,omitempty"`
+	Summary   string       `json:"summary,omitempty"`
+	Content   *Content     `json:"content,omitempty"`
+	Published string       `json:"published,omitempty"`
+	Updated   string       `json:"updated,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	UID       string       `json:"uid,omitempty"`
+	Category  []string     `json:"category,omitempty"`
+	Photo     []string     `json:"photo,omitempty"`
+	Author    *hcard.HCard `json:"author,omitempty"`
+	PostType  string       `json:"postType,omitempty"`
+	PostName  string       `json:"postName,omitempty"`
+
+	item *mf.Microformat
+}
+
+// Microformat returns the parsed h-entry the HEntry was built from.
+func (e *HEntry) Microformat() *mf.Microformat {
+	return e.item
+}
+
+// Content represents the content of a h-entry
+type Content struct {
+	Text string `json:"text,omitempty"`
+	HTML string `json:"html,omitempty"`
+}
+
+// Fetch returns the primary H-Entry found at the given URL, together with
+// the response header.
+func Fetch(link string) (*HEntry, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e, err := FromDocument(d, res.Request.URL)
+	if err != nil {
+		return nil, &res.Header, err
+	}
+
+	return e, &res.Header, nil
+}
+
+// FromDocument returns the primary H-Entry of a document retrieved from
+// the given URL
+func FromDocument(d *goquery.Document, u *url.URL) (*HEntry, error) {
+	i := Primary(mf.ParseNode(d.Get(0), u), u)
+	if i == nil {
+		return nil, fmt.Errorf("no h-entry found")
+	}
+
+	e := FromMicroformat(i)
+	e.Source = u.String()
+	return e, nil
+}
+
+// Primary returns the primary h-entry of the parsed page retrieved from the
+// given URL: the one with url matching the page URL if there is one, the
+// first h-entry on the page otherwise.
+func Primary(d *mf.Data, u *url.URL) *mf.Microformat {
+	return mf2.Primary(d, u, "h-entry")
+}
+
+// FromMicroformat returns the HEntry described by the parsed h-entry
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HEntry {
+	e := HEntry{
+		Name:      mf2.Property(i, "name"),
+		Summary:   mf2.Property(i, "summary"),
+		Published: mf2.Property(i, "published"),
+		Updated:   mf2.Property(i, "updated"),
+		URL:       mf2.Property(i, "url"),
+		UID:       mf2.Property(i, "uid"),
+		Category:  mf2.Properties(i, "category"),
+		Photo:     mf2.Properties(i, "photo"),
+		PostType:  posttype.Type(i),
+		PostName:  posttype.Name(i),
+		item:      i,
+	}
+
+	text, html := mf2.Property(i, "content"), mf2.HTML(i, "content")
+	if text != "" || html != "" {
+		e.Content = &Content{Text: text, HTML: html}
+	}
+
+	e.Author = hcard.FromProperty(i, "author")
+
+	return &e
+}
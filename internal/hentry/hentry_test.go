@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package hentry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	e, _, err := Fetch(s.URL + "/post.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tests := map[string]struct {
+		got  string
+		want string
+	}{
+		"name":         {e.Name, "Walking the dog"},
+		"summary":      {e.Summary, "A short story about a long walk."},
+		"published":    {e.Published, "2023-04-01T10:00:00+02:00"},
+		"updated":      {e.Updated, "2023-04-02T08:30:00+02:00"},
+		"url":          {e.URL, s.URL + "/2023/walking-the-dog/"},
+		"uid":          {e.UID, s.URL + "/2023/walking-the-dog/"},
+		"content text": {e.Content.Text, "We went all the way to the lake.\n      the lake"},
+		"author name":  {e.Author.PName, "Jane Doe"},
+		"author photo": {e.Author.Photo, s.URL + "/me.jpg"},
+		"post type":    {e.PostType, "photo"},
+		"post name":    {e.PostName, "Walking the dog"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, tc.got)
+			}
+		})
+	}
+
+	if want := []string{"dogs", "walks"}; !reflect.DeepEqual(e.Category, want) {
+		t.Fatalf("want categories %v, got %v", want, e.Category)
+	}
+
+	if want := []string{s.URL + "/lake.jpg"}; !reflect.DeepEqual(e.Photo, want) {
+		t.Fatalf("want photos %v, got %v", want, e.Photo)
+	}
+}
+
+func TestPrimary(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	e, _, err := Fetch(s.URL + "/list.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	want := "First note, and the one this page is about."
+	if e.Content.Text != want {
+		t.Fatalf("want %q, got %q", want, e.Content.Text)
+	}
+	if e.Author.PName != "Bob" {
+		t.Fatalf("want author Bob, got %q", e.Author.PName)
+	}
+}
+
+func TestNoEntry(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	if _, _, err := Fetch(s.URL + "/404.html"); err == nil {
+		t.Fatal("want error, got none")
+	}
+}
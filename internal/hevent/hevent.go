@@ -0,0 +1,123 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package hevent provides handling for h-event microformats.
+package hevent
+
+import (
+	"net/http"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	mf "willnorris.com/go/microformats"
+)
+
+// The types of event location
+const (
+	LocationText = "text"
+	LocationCard = "h-card"
+	LocationAdr  = "h-adr"
+	LocationGeo  = "h-geo"
+)
+
+// HEvent represents a h-event
+type HEvent struct {
+	Source      string       `json:"source,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Start       string       `json:"start,omitempty"`
+	End         string       `json:"end,omitempty"`
+	Duration    string       `json:"duration,omitempty"`
+	Location    *Location    `json:"location,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	UID         string       `json:"uid,omitempty"`
+	Category    []string     `json:"category,omitempty"`
+	Organizer   *hcard.HCard `json:"organizer,omitempty"`
+}
+
+// Location represents the location of a h-event; Type tells which of the
+// other fields is set.
+type Location struct {
+	Type string       `json:"type"`
+	Name string       `json:"name,omitempty"`
+	Card *hcard.HCard `json:"card,omitempty"`
+	Adr  *hcard.Adr   `json:"adr,omitempty"`
+	Geo  *Geo         `json:"geo,omitempty"`
+}
+
+// Geo represents a h-geo
+type Geo struct {
+	Latitude  string `json:"latitude,omitempty"`
+	Longitude string `json:"longitude,omitempty"`
+	Altitude  string `json:"altitude,omitempty"`
+}
+
+// Fetch returns the primary H-Event found at the given URL, together with
+// the response header.
+func Fetch(link string) (*HEvent, *http.Header, error) {
+	i, u, hd, err := mf2.FetchPrimary(link, "h-event")
+	if err != nil {
+		return nil, hd, err
+	}
+
+	e := FromMicroformat(i)
+	e.Source = u.String()
+	return e, hd, nil
+}
+
+// FromMicroformat returns the HEvent described by the parsed h-event
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HEvent {
+	e := HEvent{
+		Name:        mf2.Property(i, "name"),
+		Summary:     mf2.Property(i, "summary"),
+		Description: mf2.Property(i, "description"),
+		Start:       mf2.Property(i, "start"),
+		End:         mf2.Property(i, "end"),
+		Duration:    mf2.Property(i, "duration"),
+		Location:    location(i),
+		URL:         mf2.Property(i, "url"),
+		UID:         mf2.Property(i, "uid"),
+		Category:    mf2.Properties(i, "category"),
+	}
+
+	e.Organizer = hcard.FromProperty(i, "organizer")
+
+	return &e
+}
+
+// location returns the location of the h-event, if any
+func location(i *mf.Microformat) *Location {
+	l := mf2.Embedded(i, "location")
+	switch {
+	case mf2.HasType(l, "h-card"):
+		return &Location{Type: LocationCard, Name: l.Value, Card: hcard.FromMicroformat(l)}
+	case mf2.HasType(l, "h-adr"):
+		a := hcard.AdrFromMicroformat(l)
+		return &Location{Type: LocationAdr, Name: l.Value, Adr: &a}
+	case mf2.HasType(l, "h-geo"):
+		return &Location{Type: LocationGeo, Name: l.Value, Geo: &Geo{
+			Latitude:  mf2.Property(l, "latitude"),
+			Longitude: mf2.Property(l, "longitude"),
+			Altitude:  mf2.Property(l, "altitude"),
+		}}
+	}
+
+	if name := mf2.Property(i, "location"); name != "" {
+		return &Location{Type: LocationText, Name: name}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package hevent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	mf "willnorris.com/go/microformats"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	e, _, err := Fetch(s.URL + "/event.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tests := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"name":           {e.Name, "IndieWebCamp Springfield"},
+		"summary":        {e.Summary, "Two days of building our own websites."},
+		"start":          {e.Start, "2026-11-05 09:00-07:00"},
+		"end":            {e.End, "2026-11-06 17:00-07:00"},
+		"url":            {e.URL, s.URL + "/events/iwc.html"},
+		"category":       {e.Category, []string{"indieweb"}},
+		"location type":  {e.Location.Type, LocationCard},
+		"location name":  {e.Location.Card.PName, "Springfield Library"},
+		"location adr":   {e.Location.Card.Locality, "Springfield"},
+		"organizer name": {e.Organizer.PName, "Jane Doe"},
+		"organizer url":  {e.Organizer.URL, []string{"https://jane.example/"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Fatalf("want %v, got %v", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	d, _, err := mf2.Fetch(s.URL + "/event.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	events := mf2.Find(d.Items, "h-event")
+
+	tests := map[string]struct {
+		item *mf.Microformat
+		want *Location
+	}{
+		"geo": {events[1], &Location{Type: LocationGeo, Name: "44.05,\n      -123.09", Geo: &Geo{Latitude: "44.05", Longitude: "-123.09"}}},
+		"adr": {&mf.Microformat{Properties: map[string][]interface{}{"location": {&mf.Microformat{
+			Type:       []string{"h-adr"},
+			Properties: map[string][]interface{}{"locality": {"Springfield"}},
+		}}}}, &Location{Type: LocationAdr, Adr: &hcard.Adr{Locality: "Springfield"}}},
+		"text": {&mf.Microformat{Properties: map[string][]interface{}{"location": {"The park"}}}, &Location{Type: LocationText, Name: "The park"}},
+		"none": {&mf.Microformat{}, nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := location(tc.item)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package hfeed provides handling for h-feed microformats.
+package hfeed
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hentry"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	"github.com/PuerkitoBio/goquery"
+	mf "willnorris.com/go/microformats"
+)
+
+// HFeed represents a h-feed
+type HFeed struct {
+	Source  string       `json:"source,omitempty"`
+	Name    string       `json:"name,omitempty"`
+	Photo   string       `json:"photo,omitempty"`
+	Author  *hcard.HCard `json:"author,omitempty"`
+	Entries []Entry      `json:"entries"`
+	Next    string       `json:"next,omitempty"`
+}
+
+// Entry represents a h-entry in a h-feed
+type Entry struct {
+	Name      string       `json:"name,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	Published string       `json:"published,omitempty"`
+	Author    *hcard.HCard `json:"author,omitempty"`
+	PostType  string       `json:"postType,omitempty"`
+	PostName  string       `json:"postName,omitempty"`
+}
+
+// Fetch returns the H-Feed found at the given URL, together with the
+// response header. No more than limit entries are returned; if the first
+// page holds less than that, up to pages pages are fetched following the
+// rel=next links.
+func Fetch(link string, limit, pages int) (*HFeed, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	f, hd, next, err := fetchPage(u.String())
+	if err != nil {
+		return nil, hd, err
+	}
+
+	visited := map[string]bool{f.Source: true}
+	for page := 1; page < pages && len(f.Entries) < limit && next != "" && !visited[next]; page++ {
+		visited[next] = true
+		p, _, n, err := fetchPage(next)
+		if err != nil {
+			break
+		}
+		f.Entries = append(f.Entries, p.Entries...)
+		next = n
+	}
+
+	if len(f.Entries) > limit {
+		f.Entries = f.Entries[:limit]
+	}
+	if !visited[next] {
+		f.Next = next
+	}
+
+	return f, hd, nil
+}
+
+// fetchPage returns the H-Feed found at the given URL, the response header
+// and the rel=next link of the page.
+func fetchPage(link string) (*HFeed, *http.Header, string, error) {
+	res, err := http.Get(link)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	data := mf.ParseNode(d.Get(0), res.Request.URL)
+	f, err := FromData(data)
+	if err != nil {
+		return nil, &res.Header, "", err
+	}
+	f.Source = res.Request.URL.String()
+	if f.Name == "" {
+		f.Name = strings.TrimSpace(d.Find("title").First().Text())
+	}
+
+	var next string
+	if nn := data.Rels["next"]; len(nn) > 0 {
+		next = nn[0]
+	}
+
+	return f, &res.Header, next, nil
+}
+
+// FromData returns the first H-Feed in the parsed page, or the implied feed
+// of the top-level h-entries if the page has no explicit h-feed.
+func FromData(d *mf.Data) (*HFeed, error) {
+	if feeds := mf2.Find(d.Items, "h-feed"); len(feeds) > 0 {
+		return FromMicroformat(feeds[0]), nil
+	}
+
+	var f HFeed
+	for _, i := range d.Items {
+		if mf2.HasType(i, "h-entry") {
+			f.Entries = append(f.Entries, entry(i, nil))
+		}
+	}
+	if len(f.Entries) == 0 {
+		return nil, fmt.Errorf("no h-feed found")
+	}
+	return &f, nil
+}
+
+// FromMicroformat returns the HFeed described by the parsed h-feed
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HFeed {
+	f := HFeed{
+		Name:    mf2.Property(i, "name"),
+		Photo:   mf2.Property(i, "photo"),
+		Entries: []Entry{},
+	}
+
+	f.Author = hcard.FromProperty(i, "author")
+	if f.Photo == "" && f.Author != nil {
+		f.Photo = f.Author.Photo
+	}
+
+	for _, c := range i.Children {
+		if mf2.HasType(c, "h-entry") {
+			f.Entries = append(f.Entries, entry(c, f.Author))
+		}
+	}
+	return &f
+}
+
+// entry returns the Entry described by the parsed h-entry microformat, the
+// feed author taken as its author if it has none
+func entry(i *mf.Microformat, author *hcard.HCard) Entry {
+	e := hentry.FromMicroformat(i)
+	if e.Author == nil {
+		e.Author = author
+	}
+	return Entry{
+		Name:      e.Name,
+		URL:       e.URL,
+		Published: e.Published,
+		Author:    e.Author,
+		PostType:  e.PostType,
+		PostName:  e.PostName,
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package hfeed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+)
+
+func TestFetch(t *testing.T) {
+	tests := map[string]struct {
+		link    string
+		limit   int
+		pages   int
+		entries []string
+		next    string
+	}{
+		"one page":      {"/feed.html", 10, 1, []string{"Walking the dog", "Feeding the cat"}, "/feed2.html"},
+		"limited":       {"/feed.html", 1, 1, []string{"Walking the dog"}, "/feed2.html"},
+		"two pages":     {"/feed.html", 10, 2, []string{"Walking the dog", "Feeding the cat", "Washing the car"}, ""},
+		"no loops":      {"/feed.html", 10, 5, []string{"Walking the dog", "Feeding the cat", "Washing the car"}, ""},
+		"limit reached": {"/feed.html", 2, 5, []string{"Walking the dog", "Feeding the cat"}, "/feed2.html"},
+		"implied":       {"/implied.html", 10, 1, []string{"Second note.", "First note."}, ""},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, _, err := Fetch(s.URL+tc.link, tc.limit, tc.pages)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if len(f.Entries) != len(tc.entries) {
+				t.Fatalf("want %d entries, got %d", len(tc.entries), len(f.Entries))
+			}
+			for i, e := range f.Entries {
+				if e.Name != tc.entries[i] {
+					t.Fatalf("want entry %d to be %q, got %q", i, tc.entries[i], e.Name)
+				}
+			}
+
+			want := tc.next
+			if want != "" {
+				want = s.URL + want
+			}
+			if f.Next != want {
+				t.Fatalf("want next %q, got %q", want, f.Next)
+			}
+		})
+	}
+}
+
+func TestFeedProperties(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	f, _, err := Fetch(s.URL+"/feed.html", 10, 1)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if f.Name != "Latest posts" {
+		t.Fatalf("want name %q, got %q", "Latest posts", f.Name)
+	}
+	if f.Photo != s.URL+"/me.jpg" {
+		t.Fatalf("want photo %q, got %q", s.URL+"/me.jpg", f.Photo)
+	}
+	if f.Author == nil || f.Author.PName != "Jane Doe" {
+		t.Fatalf("want author Jane Doe, got %v", f.Author)
+	}
+	if f.Entries[0].Published != "2023-04-01T10:00:00+02:00" {
+		t.Fatalf("want published date, got %q", f.Entries[0].Published)
+	}
+	if a := f.Entries[0].Author; a == nil || a.PName != "Jane Doe" {
+		t.Fatalf("want entry author inherited from the feed, got %v", a)
+	}
+	if a := f.Entries[1].Author; a == nil || a.PName != "Guest Author" {
+		t.Fatalf("want entry author Guest Author, got %v", a)
+	}
+
+	f, _, err = Fetch(s.URL+"/implied.html", 10, 1)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if f.Name != "Bob's notes" {
+		t.Fatalf("want implied name %q, got %q", "Bob's notes", f.Name)
+	}
+}
+
+func TestAuthor(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	tests := map[string]struct {
+		link string
+		want hcard.HCard
+	}{
+		"text": {"/text_author.html", hcard.HCard{PName: "Jane Doe"}},
+		"url":  {"/url_author.html", hcard.HCard{URL: []string{s.URL + "/"}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, _, err := Fetch(s.URL+tc.link, 10, 1)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if f.Author == nil || !reflect.DeepEqual(*f.Author, tc.want) {
+				t.Fatalf("want author %+v, got %+v", tc.want, f.Author)
+			}
+		})
+	}
+}
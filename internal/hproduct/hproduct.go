@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package hproduct provides handling for h-product microformats.
+package hproduct
+
+import (
+	"net/http"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	mf "willnorris.com/go/microformats"
+)
+
+// HProduct represents a h-product
+type HProduct struct {
+	Source      string       `json:"source,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	Price       string       `json:"price,omitempty"`
+	Brand       *hcard.HCard `json:"brand,omitempty"`
+	Photo       []string     `json:"photo,omitempty"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Identifier  []string     `json:"identifier,omitempty"`
+	Category    []string     `json:"category,omitempty"`
+}
+
+// Fetch returns the primary HProduct found at the given URL, together with
+// the response header.
+func Fetch(link string) (*HProduct, *http.Header, error) {
+	i, u, hd, err := mf2.FetchPrimary(link, "h-product")
+	if err != nil {
+		return nil, hd, err
+	}
+
+	v := FromMicroformat(i)
+	v.Source = u.String()
+	return v, hd, nil
+}
+
+// FromMicroformat returns the HProduct described by the parsed h-product
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HProduct {
+	return &HProduct{
+		Name:        mf2.Property(i, "name"),
+		Price:       mf2.Property(i, "price"),
+		Brand:       hcard.FromProperty(i, "brand"),
+		Photo:       mf2.Properties(i, "photo"),
+		Description: mf2.Property(i, "description"),
+		URL:         mf2.Property(i, "url"),
+		Identifier:  mf2.Properties(i, "identifier"),
+		Category:    mf2.Properties(i, "category"),
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package hproduct
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	p, _, err := Fetch(s.URL + "/product.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tests := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"name":        {p.Name, "Acme Kettle"},
+		"price":       {p.Price, "19.99"},
+		"brand":       {p.Brand.PName, "ACME"},
+		"photo":       {p.Photo, []string{s.URL + "/kettle.jpg", s.URL + "/kettle-side.jpg"}},
+		"description": {p.Description, "Whistles when the water boils."},
+		"url":         {p.URL, s.URL + "/product.html"},
+		"category":    {p.Category, []string{"kitchen"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Fatalf("want %v, got %v", tc.want, tc.got)
+			}
+		})
+	}
+}
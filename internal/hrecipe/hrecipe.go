@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package hrecipe provides handling for h-recipe microformats.
+package hrecipe
+
+import (
+	"net/http"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hentry"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	mf "willnorris.com/go/microformats"
+)
+
+// HRecipe represents a h-recipe
+type HRecipe struct {
+	Source       string          `json:"source,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	Summary      string          `json:"summary,omitempty"`
+	Ingredients  []string        `json:"ingredients,omitempty"`
+	Yield        string          `json:"yield,omitempty"`
+	Duration     string          `json:"duration,omitempty"`
+	Instructions *hentry.Content `json:"instructions,omitempty"`
+	Nutrition    []string        `json:"nutrition,omitempty"`
+	Photo        []string        `json:"photo,omitempty"`
+	Author       *hcard.HCard    `json:"author,omitempty"`
+	Published    string          `json:"published,omitempty"`
+	URL          string          `json:"url,omitempty"`
+	Category     []string        `json:"category,omitempty"`
+}
+
+// Fetch returns the primary HRecipe found at the given URL, together with
+// the response header.
+func Fetch(link string) (*HRecipe, *http.Header, error) {
+	i, u, hd, err := mf2.FetchPrimary(link, "h-recipe")
+	if err != nil {
+		return nil, hd, err
+	}
+
+	v := FromMicroformat(i)
+	v.Source = u.String()
+	return v, hd, nil
+}
+
+// FromMicroformat returns the HRecipe described by the parsed h-recipe
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HRecipe {
+	r := HRecipe{
+		Name:        mf2.Property(i, "name"),
+		Summary:     mf2.Property(i, "summary"),
+		Ingredients: mf2.Properties(i, "ingredient"),
+		Yield:       mf2.Property(i, "yield"),
+		Duration:    mf2.Property(i, "duration"),
+		Nutrition:   mf2.Properties(i, "nutrition"),
+		Photo:       mf2.Properties(i, "photo"),
+		Author:      hcard.FromProperty(i, "author"),
+		Published:   mf2.Property(i, "published"),
+		URL:         mf2.Property(i, "url"),
+		Category:    mf2.Properties(i, "category"),
+	}
+
+	text, html := mf2.Property(i, "instructions"), mf2.HTML(i, "instructions")
+	if text != "" || html != "" {
+		r.Instructions = &hentry.Content{Text: text, HTML: html}
+	}
+
+	return &r
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package hrecipe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	r, _, err := Fetch(s.URL + "/recipe.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tests := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"name":         {r.Name, "Pancakes"},
+		"summary":      {r.Summary, "Quick breakfast pancakes."},
+		"ingredients":  {r.Ingredients, []string{"2 eggs", "250 ml milk", "150 g flour"}},
+		"yield":        {r.Yield, "8 pancakes"},
+		"duration":     {r.Duration, "PT30M"},
+		"instructions": {strings.Contains(r.Instructions.HTML, "<li>Fry.</li>"), true},
+		"photo":        {r.Photo, []string{s.URL + "/pancakes.jpg"}},
+		"author":       {r.Author.PName, "Jane Doe"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Fatalf("want %v, got %v", tc.want, tc.got)
+			}
+		})
+	}
+}
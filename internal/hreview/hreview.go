@@ -0,0 +1,147 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package hreview provides handling for h-review microformats.
+package hreview
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hentry"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	mf "willnorris.com/go/microformats"
+)
+
+// The default bounds of a rating, see http://microformats.org/wiki/h-review
+const (
+	DefaultBest  = 5
+	DefaultWorst = 1
+)
+
+// HReview represents a h-review
+type HReview struct {
+	Source    string          `json:"source,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Item      *Item           `json:"item,omitempty"`
+	Rating    *float64        `json:"rating,omitempty"`
+	Best      *float64        `json:"best,omitempty"`
+	Worst     *float64        `json:"worst,omitempty"`
+	Reviewer  *hcard.HCard    `json:"reviewer,omitempty"`
+	Summary   string          `json:"summary,omitempty"`
+	Content   *hentry.Content `json:"content,omitempty"`
+	Published string          `json:"published,omitempty"`
+	URL       string          `json:"url,omitempty"`
+	Category  []string        `json:"category,omitempty"`
+}
+
+// Item represents the item reviewed; Type is the type of the nested
+// microformat (e.g. "h-product" or "h-card"), or empty if the item is just
+// plain text.
+type Item struct {
+	Type  string `json:"type,omitempty"`
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Photo string `json:"photo,omitempty"`
+}
+
+// Fetch returns the primary HReview found at the given URL, together with
+// the response header.
+func Fetch(link string) (*HReview, *http.Header, error) {
+	i, u, hd, err := mf2.FetchPrimary(link, "h-review")
+	if err != nil {
+		return nil, hd, err
+	}
+
+	v := FromMicroformat(i)
+	v.Source = u.String()
+	return v, hd, nil
+}
+
+// FromMicroformat returns the HReview described by the parsed h-review
+// microformat.
+func FromMicroformat(i *mf.Microformat) *HReview {
+	r := HReview{
+		Name:      mf2.Property(i, "name"),
+		Item:      item(i),
+		Rating:    number(i, "rating"),
+		Summary:   mf2.Property(i, "summary"),
+		Published: mf2.Property(i, "published"),
+		URL:       mf2.Property(i, "url"),
+		Category:  mf2.Properties(i, "category"),
+	}
+
+	if r.Rating != nil {
+		r.Best, r.Worst = number(i, "best"), number(i, "worst")
+		if r.Best == nil {
+			r.Best = float(DefaultBest)
+		}
+		if r.Worst == nil {
+			r.Worst = float(DefaultWorst)
+		}
+	}
+
+	text, html := mf2.Property(i, "content"), mf2.HTML(i, "content")
+	if text != "" || html != "" {
+		r.Content = &hentry.Content{Text: text, HTML: html}
+	}
+
+	// the reviewer used to be marked up as p-reviewer
+	r.Reviewer = hcard.FromProperty(i, "author")
+	if r.Reviewer == nil {
+		r.Reviewer = hcard.FromProperty(i, "reviewer")
+	}
+
+	return &r
+}
+
+// item returns the item reviewed, if any
+func item(i *mf.Microformat) *Item {
+	if m := mf2.Embedded(i, "item"); m != nil {
+		it := Item{
+			Name:  mf2.Property(m, "name"),
+			URL:   mf2.Property(m, "url"),
+			Photo: mf2.Property(m, "photo"),
+		}
+		if len(m.Type) > 0 {
+			it.Type = m.Type[0]
+		}
+		if it.Name == "" {
+			it.Name = m.Value
+		}
+		return &it
+	}
+
+	if name := mf2.Property(i, "item"); name != "" {
+		return &Item{Name: name}
+	}
+	return nil
+}
+
+// number returns the first value of the property as a number, or nil if
+// there is no numeric value
+func number(i *mf.Microformat, property string) *float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(mf2.Property(i, property)), 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func float(v float64) *float64 {
+	return &v
+}
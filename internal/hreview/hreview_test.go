@@ -0,0 +1,85 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package hreview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	r, _, err := Fetch(s.URL + "/review.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tests := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"name":          {r.Name, "A kettle that whistles"},
+		"item":          {*r.Item, Item{Type: "h-product", Name: "Acme Kettle", URL: "https://acme.example/kettle", Photo: s.URL + "/kettle.jpg"}},
+		"rating":        {*r.Rating, 4.0},
+		"best":          {*r.Best, 10.0},
+		"default worst": {*r.Worst, 1.0},
+		"content":       {r.Content.HTML, "Boils water. <b>Loudly.</b>"},
+		"reviewer":      {r.Reviewer.PName, "Jane Doe"},
+		"published":     {r.Published, "2026-03-01"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Fatalf("want %v, got %v", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestFromMicroformat(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	d, _, err := mf2.Fetch(s.URL + "/review.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	u, _ := url.Parse(s.URL)
+	if p := mf2.Primary(d, u, "h-review"); p != d.Items[0] {
+		t.Fatalf("want the first h-review to be primary")
+	}
+
+	r := FromMicroformat(d.Items[1])
+	if !reflect.DeepEqual(*r.Item, Item{Name: "The old kettle"}) {
+		t.Fatalf("want plain text item, got %+v", r.Item)
+	}
+	if *r.Rating != 2.5 || *r.Best != DefaultBest || *r.Worst != DefaultWorst {
+		t.Fatalf("want rating 2.5 of %d to %d, got %v of %v to %v", DefaultWorst, DefaultBest, *r.Rating, *r.Worst, *r.Best)
+	}
+	if r.Reviewer.PName != "John" {
+		t.Fatalf("want reviewer John, got %+v", r.Reviewer)
+	}
+}
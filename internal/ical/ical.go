@@ -0,0 +1,260 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package ical provides conversion of h-events to iCalendar (RFC 5545).
+package ical
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/contentline"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hevent"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/strs"
+)
+
+// ContentType is the media type of iCalendar
+const ContentType = "text/calendar"
+
+const (
+	prodID = "-//evgenykuznetsov.org//indieweb-glue//EN"
+
+	dateFormat     = "20060102"
+	floatingFormat = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+)
+
+// now returns the current time, used for DTSTAMP
+var now = time.Now
+
+// the kinds of date and time values
+const (
+	kindDate     = iota // date without time
+	kindFloating        // local time without UTC offset
+	kindAbsolute        // time with UTC offset
+)
+
+// timeLayouts are the layouts of mf2 date and time values (with the date and
+// time separated by "T"), by kind
+var timeLayouts = map[int][]string{
+	kindDate:     {"2006-01-02"},
+	kindFloating: {"2006-01-02T15:04:05", "2006-01-02T15:04"},
+	kindAbsolute: {
+		"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04:05Z0700", "2006-01-02T15:04Z0700",
+		"2006-01-02T15:04:05Z07", "2006-01-02T15:04Z07",
+	},
+}
+
+// timeOnly matches the time values without date
+var timeOnly = regexp.MustCompile(`^\d{2}:\d{2}`)
+
+// duration matches the durations allowed by RFC 5545
+var duration = regexp.MustCompile(`^[+-]?P(\d+W|(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?)$`)
+
+// Encode returns the iCalendar with a VEVENT for each of the h-events
+func Encode(events ...*hevent.HEvent) []byte {
+	var b strings.Builder
+	contentline.Write(&b, "BEGIN:VCALENDAR")
+	contentline.Write(&b, "VERSION:2.0")
+	contentline.Write(&b, "PRODID:"+prodID)
+	for _, e := range events {
+		for _, l := range event(e) {
+			contentline.Write(&b, l)
+		}
+	}
+	contentline.Write(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// event returns the unfolded content lines of the VEVENT
+func event(e *hevent.HEvent) []string {
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + contentline.EscapeText(uid(e)),
+		"DTSTAMP:" + now().UTC().Format(utcFormat),
+	}
+	add := func(name, value string) {
+		if value != "" {
+			lines = append(lines, name+":"+value)
+		}
+	}
+
+	start, startKind, startOK := parseTime(e.Start)
+	if startOK {
+		lines = append(lines, dateTime("DTSTART", start, startKind))
+	}
+
+	end, endKind, endOK := parseTime(e.End)
+	if !endOK && startOK && startKind != kindDate && timeOnly.MatchString(e.End) {
+		// the end time is on the start date
+		end, endKind, endOK = parseTime(start.Format("2006-01-02") + "T" + e.End)
+	}
+	if endOK && endKind == kindFloating && startOK && startKind == kindAbsolute {
+		// the end time is in the same time zone as the start time
+		end = time.Date(end.Year(), end.Month(), end.Day(), end.Hour(), end.Minute(), end.Second(), 0, start.Location())
+		endKind = kindAbsolute
+	}
+	switch {
+	case !startOK:
+		// neither DTEND nor DURATION make sense without DTSTART
+	case endOK && (endKind == kindDate) != (startKind == kindDate):
+		// DTSTART and DTEND must be of the same value type, drop the end
+	case endOK && endKind == kindDate:
+		// the end date of an h-event is inclusive, and DTEND is not
+		lines = append(lines, dateTime("DTEND", end.AddDate(0, 0, 1), endKind))
+	case endOK:
+		lines = append(lines, dateTime("DTEND", end, endKind))
+	case duration.MatchString(e.Duration):
+		add("DURATION", e.Duration)
+	}
+
+	add("SUMMARY", contentline.EscapeText(e.Name))
+	description := e.Description
+	if description == "" {
+		description = e.Summary
+	}
+	add("DESCRIPTION", contentline.EscapeText(description))
+	add("LOCATION", contentline.EscapeText(location(e.Location)))
+	if l := e.Location; l != nil && l.Geo != nil && l.Geo.Latitude != "" && l.Geo.Longitude != "" {
+		add("GEO", l.Geo.Latitude+";"+l.Geo.Longitude)
+	}
+	if len(e.Category) > 0 {
+		categories := make([]string, len(e.Category))
+		for i, c := range e.Category {
+			categories[i] = contentline.EscapeText(c)
+		}
+		add("CATEGORIES", strings.Join(categories, ","))
+	}
+	add("URL", e.URL)
+	if o := organizer(e); o != "" {
+		lines = append(lines, o)
+	}
+
+	return append(lines, "END:VEVENT")
+}
+
+// uid returns the unique identifier of the event: its uid or url, or a hash
+// of its properties if it has neither
+func uid(e *hevent.HEvent) string {
+	switch {
+	case e.UID != "":
+		return e.UID
+	case e.URL != "":
+		return e.URL
+	}
+	h := sha1.Sum([]byte(strings.Join([]string{e.Source, e.Name, e.Start}, "\n")))
+	return fmt.Sprintf("%x@indieweb-glue", h)
+}
+
+// parseTime parses the mf2 date and time value and tells its kind
+func parseTime(s string) (time.Time, int, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) > 10 && (s[10] == ' ' || s[10] == 't') {
+		s = s[:10] + "T" + s[11:]
+	}
+	s = strings.Replace(s, "z", "Z", 1)
+
+	for _, kind := range []int{kindDate, kindFloating, kindAbsolute} {
+		for _, layout := range timeLayouts[kind] {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, kind, true
+			}
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+// dateTime returns the content line of a date and time property; times with
+// UTC offset are converted to UTC, local times are left floating
+func dateTime(name string, t time.Time, kind int) string {
+	switch kind {
+	case kindDate:
+		return name + ";VALUE=DATE:" + t.Format(dateFormat)
+	case kindFloating:
+		return name + ":" + t.Format(floatingFormat)
+	}
+	return name + ":" + t.UTC().Format(utcFormat)
+}
+
+// location returns the text description of the event location
+func location(l *hevent.Location) string {
+	if l == nil {
+		return ""
+	}
+
+	var parts []string
+	add := func(ss ...string) {
+		for _, s := range ss {
+			if s != "" && !strs.Contains(parts, s) {
+				parts = append(parts, s)
+			}
+		}
+	}
+
+	switch {
+	case l.Card != nil:
+		add(l.Card.PName)
+		for _, a := range l.Card.Adr {
+			add(address(a)...)
+		}
+		add(l.Card.Locality, l.Card.Region, l.Card.CountryName)
+	case l.Adr != nil:
+		add(address(*l.Adr)...)
+	}
+	if len(parts) == 0 {
+		add(l.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// address returns the parts of the address worth mentioning in the location
+func address(a hcard.Adr) []string {
+	if a.StreetAddress == "" && a.Locality == "" && a.Region == "" && a.CountryName == "" {
+		return []string{a.Label}
+	}
+	return []string{a.StreetAddress, a.Locality, a.Region, a.PostalCode, a.CountryName}
+}
+
+// organizer returns the ORGANIZER content line of the event, or the empty
+// string if the organizer has no email or URL to use as the address
+func organizer(e *hevent.HEvent) string {
+	o := e.Organizer
+	if o == nil {
+		return ""
+	}
+
+	var addr string
+	switch {
+	case len(o.Email) > 0:
+		addr = o.Email[0]
+		if !strings.HasPrefix(addr, "mailto:") {
+			addr = "mailto:" + addr
+		}
+	case len(o.URL) > 0:
+		addr = o.URL[0]
+	default:
+		return ""
+	}
+
+	if o.PName == "" {
+		return "ORGANIZER:" + addr
+	}
+	return "ORGANIZER;CN=\"" + contentline.EscapeParam(o.PName) + "\":" + addr
+}
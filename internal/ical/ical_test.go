@@ -0,0 +1,104 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/contentline"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hevent"
+)
+
+func TestEncode(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	e := &hevent.HEvent{
+		Name:      "IndieWebCamp; Springfield",
+		Summary:   "Two days of building our own websites.",
+		Start:     "2026-11-05 09:00-07:00",
+		End:       "2026-11-06 17:00-07:00",
+		URL:       "https://events.example/iwc",
+		Category:  []string{"indieweb", "camp"},
+		Location:  &hevent.Location{Type: hevent.LocationCard, Card: &hcard.HCard{PName: "Springfield Library", Locality: "Springfield"}},
+		Organizer: &hcard.HCard{PName: "Jane Doe", Email: []string{"jane@example.com"}},
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + prodID,
+		"BEGIN:VEVENT",
+		"UID:https://events.example/iwc",
+		"DTSTAMP:20261001T120000Z",
+		"DTSTART:20261105T160000Z",
+		"DTEND:20261107T000000Z",
+		`SUMMARY:IndieWebCamp\; Springfield`,
+		"DESCRIPTION:Two days of building our own websites.",
+		`LOCATION:Springfield Library\, Springfield`,
+		"CATEGORIES:indieweb,camp",
+		"URL:https://events.example/iwc",
+		`ORGANIZER;CN="Jane Doe":mailto:jane@example.com`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if got := string(Encode(e)); got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
+
+func TestDates(t *testing.T) {
+	tests := map[string]struct {
+		start, end, duration string
+		want                 []string
+	}{
+		"utc offsets":       {"2026-11-05T09:00:00+02:00", "2026-11-05T11:30+0200", "", []string{"DTSTART:20261105T070000Z", "DTEND:20261105T093000Z"}},
+		"zulu":              {"2026-11-05t09:00z", "", "", []string{"DTSTART:20261105T090000Z"}},
+		"floating":          {"2026-11-05 09:00", "2026-11-05 17:00", "", []string{"DTSTART:20261105T090000", "DTEND:20261105T170000"}},
+		"end time only":     {"2026-11-05 09:00+02:00", "11:00", "", []string{"DTSTART:20261105T070000Z", "DTEND:20261105T090000Z"}},
+		"end without zone":  {"2026-11-05 09:00-07:00", "2026-11-05 17:00", "", []string{"DTSTART:20261105T160000Z", "DTEND:20261106T000000Z"}},
+		"all day":           {"2026-11-05", "2026-11-06", "", []string{"DTSTART;VALUE=DATE:20261105", "DTEND;VALUE=DATE:20261107"}},
+		"mixed value types": {"2026-11-05", "2026-11-06 17:00", "", []string{"DTSTART;VALUE=DATE:20261105"}},
+		"duration":          {"2026-11-05 09:00", "", "PT2H30M", []string{"DTSTART:20261105T090000", "DURATION:PT2H30M"}},
+		"bad duration":      {"2026-11-05 09:00", "", "P1Y", []string{"DTSTART:20261105T090000"}},
+		"no start":          {"", "2026-11-05 09:00", "PT1H", nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, l := range event(&hevent.HEvent{URL: "https://events.example/", Start: tc.start, End: tc.end, Duration: tc.duration}) {
+				if strings.HasPrefix(l, "DTSTART") || strings.HasPrefix(l, "DTEND") || strings.HasPrefix(l, "DURATION") {
+					got = append(got, l)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Fatalf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFolding(t *testing.T) {
+	name := strings.Repeat("Ж", 100)
+	got := string(Encode(&hevent.HEvent{Name: name}))
+
+	var unfolded strings.Builder
+	for _, l := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+		if len(l) > contentline.MaxLength {
+			t.Fatalf("line longer than %d octets: %q", contentline.MaxLength, l)
+		}
+		if strings.HasPrefix(l, " ") {
+			unfolded.WriteString(l[1:])
+			continue
+		}
+		unfolded.WriteString("\n" + l)
+	}
+
+	if !strings.Contains(unfolded.String(), "\nSUMMARY:"+name+"\n") {
+		t.Fatalf("want name %q preserved, got %q", name, got)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package icon provides discovery of site icons.
+package icon
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/manifest"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/strs"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// The sources of icons
+const (
+	RelIcon       = "icon"
+	RelAppleTouch = "apple-touch-icon"
+	RelMask       = "mask-icon"
+	RelManifest   = "manifest"
+	RelFavicon    = "favicon.ico" // the conventional /favicon.ico
+)
+
+// appleTouchSize is the size of apple-touch-icon without declared sizes
+const appleTouchSize = 180
+
+// The costs of the icons by kind, from the best to the worst; the cost of an
+// icon not smaller than the size requested is twice the difference in size
+const (
+	costScalable = 1
+	costUnknown  = 1 << 16
+	costSmaller  = 1 << 17
+	costMask     = 1 << 18
+	costFavicon  = 1 << 19
+)
+
+// Icon represents a candidate icon of a site
+type Icon struct {
+	URL      string `json:"url"`
+	Rel      string `json:"rel"`
+	Type     string `json:"type,omitempty"`
+	Sizes    []Size `json:"sizes,omitempty"`
+	Scalable bool   `json:"scalable,omitempty"`
+	Purpose  string `json:"purpose,omitempty"`
+}
+
+// Size represents a declared size of an icon
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Fetch returns the candidate icons of the page at the given URL: the ones
+// found in the document, the ones of its web app manifest, and /favicon.ico
+// as the last resort, together with the response header.
+func Fetch(link string) ([]Icon, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	icons := FromDocument(d, res.Request.URL)
+	if link := manifest.URL(d, res.Request.URL); link != "" && len(icons) > 0 {
+		if m, _, err := manifest.Load(link); err == nil {
+			// the manifest icons go before the /favicon.ico
+			last := icons[len(icons)-1]
+			icons = append(icons[:len(icons)-1], FromManifest(m)...)
+			icons = append(icons, last)
+		}
+	}
+
+	return dedup(icons), &res.Header, nil
+}
+
+// FromDocument returns the candidate icons declared with link elements of a
+// document retrieved from the given URL, followed by /favicon.ico.
+func FromDocument(d *goquery.Document, u *url.URL) []Icon {
+	base := urlnorm.Base(d, u)
+
+	var icons []Icon
+	d.Find("link[rel][href]").Each(func(_ int, s *goquery.Selection) {
+		rel, _ := s.Attr("rel")
+		href, _ := s.Attr("href")
+		typ, _ := s.Attr("type")
+		sizes, _ := s.Attr("sizes")
+
+		i := Icon{URL: urlnorm.Resolve(base, href), Type: strings.ToLower(strings.TrimSpace(typ))}
+		for _, r := range strings.Fields(strings.ToLower(rel)) {
+			switch r {
+			case "icon":
+				i.Rel = RelIcon
+			case "apple-touch-icon", "apple-touch-icon-precomposed":
+				i.Rel = RelAppleTouch
+			case "mask-icon":
+				i.Rel = RelMask
+			}
+		}
+		if i.Rel == "" || i.URL == "" {
+			return
+		}
+
+		i.Sizes, i.Scalable = parseSizes(sizes)
+		if i.Rel == RelAppleTouch && len(i.Sizes) == 0 {
+			i.Sizes = []Size{{appleTouchSize, appleTouchSize}}
+		}
+		i.Scalable = i.Scalable || isSVG(i)
+		icons = append(icons, i)
+	})
+
+	if u != nil {
+		favicon := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/favicon.ico"}
+		if link := urlnorm.Resolve(nil, favicon.String()); link != "" {
+			icons = append(icons, Icon{URL: link, Rel: RelFavicon})
+		}
+	}
+
+	return icons
+}
+
+// FromManifest returns the icons listed in the web app manifest
+func FromManifest(m *manifest.Manifest) []Icon {
+	var icons []Icon
+	for _, mi := range m.Icons {
+		i := Icon{URL: mi.Src, Rel: RelManifest, Type: mi.Type, Purpose: mi.Purpose}
+		i.Sizes, i.Scalable = parseSizes(mi.Sizes)
+		i.Scalable = i.Scalable || isSVG(i)
+		icons = append(icons, i)
+	}
+	return icons
+}
+
+// Rank returns the icons ordered from the one that fits the size best to the
+// worst one. The smallest icon not smaller than the size is the best, then
+// the scalable ones, the ones of unknown size, the smaller ones (the larger
+// the better), the monochrome ones, and /favicon.ico. Icons of types other
+// than images are dropped.
+func Rank(icons []Icon, size int) []Icon {
+	var ranked []Icon
+	for _, i := range icons {
+		if i.Type == "" || strings.HasPrefix(i.Type, "image/") {
+			ranked = append(ranked, i)
+		}
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return cost(ranked[a], size) < cost(ranked[b], size)
+	})
+	return ranked
+}
+
+// cost returns how badly the icon fits the size
+func cost(i Icon, size int) int {
+	switch {
+	case i.Rel == RelFavicon:
+		return costFavicon
+	case i.Rel == RelMask || isMonochrome(i):
+		return costMask
+	}
+
+	if i.Scalable {
+		return costScalable
+	}
+
+	best := costUnknown
+	for n, sz := range i.Sizes {
+		// doubled, so that only the exact size beats the scalable icons
+		c := 2 * (sz.Width - size)
+		if sz.Width < size {
+			c = costSmaller + size - sz.Width
+		}
+		if n == 0 || c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+// parseSizes parses the sizes attribute
+func parseSizes(s string) (sizes []Size, scalable bool) {
+	for _, f := range strings.Fields(strings.ToLower(s)) {
+		if f == "any" {
+			scalable = true
+			continue
+		}
+		wh := strings.SplitN(f, "x", 2)
+		if len(wh) != 2 {
+			continue
+		}
+		w, errW := strconv.Atoi(wh[0])
+		h, errH := strconv.Atoi(wh[1])
+		if errW == nil && errH == nil && w > 0 && h > 0 {
+			sizes = append(sizes, Size{w, h})
+		}
+	}
+	return sizes, scalable
+}
+
+// isSVG reports whether the icon is an SVG image
+func isSVG(i Icon) bool {
+	if i.Type != "" {
+		return i.Type == "image/svg+xml"
+	}
+	u, err := url.Parse(i.URL)
+	return err == nil && strings.EqualFold(path.Ext(u.Path), ".svg")
+}
+
+// isMonochrome reports whether the manifest icon is for monochrome use only
+func isMonochrome(i Icon) bool {
+	purposes := strings.Fields(i.Purpose)
+	return len(purposes) > 0 && !strs.Contains(purposes, "any") && !strs.Contains(purposes, "maskable")
+}
+
+// dedup returns the icons without the repeated URLs
+func dedup(icons []Icon) []Icon {
+	seen := map[string]bool{}
+	var unique []Icon
+	for _, i := range icons {
+		if !seen[i.URL] {
+			seen[i.URL] = true
+			unique = append(unique, i)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,83 @@
+package icon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	icons, _, err := Fetch(s.URL)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	want := []Icon{
+		{URL: "/favicon-16.png", Rel: RelIcon, Type: "image/png", Sizes: []Size{{16, 16}}},
+		{URL: "/favicon-32.png", Rel: RelIcon, Sizes: []Size{{32, 32}}},
+		{URL: "/favicon.svg", Rel: RelIcon, Type: "image/svg+xml", Scalable: true},
+		{URL: "/apple-touch-icon.png", Rel: RelAppleTouch, Sizes: []Size{{180, 180}}},
+		{URL: "/safari-pinned-tab.svg", Rel: RelMask, Scalable: true},
+		{URL: "/icon.json", Rel: RelIcon, Type: "application/json"},
+		{URL: "/android-chrome-192x192.png", Rel: RelManifest, Type: "image/png", Sizes: []Size{{192, 192}}},
+		{URL: "/android-chrome-512x512.png", Rel: RelManifest, Type: "image/png", Sizes: []Size{{512, 512}}, Purpose: "any maskable"},
+		{URL: "/monochrome.png", Rel: RelManifest, Type: "image/png", Sizes: []Size{{96, 96}}, Purpose: "monochrome"},
+		{URL: "/favicon.ico", Rel: RelFavicon},
+	}
+	for i := range want {
+		want[i].URL = s.URL + want[i].URL
+	}
+
+	if !reflect.DeepEqual(icons, want) {
+		t.Fatalf("want %+v, got %+v", want, icons)
+	}
+}
+
+func TestRank(t *testing.T) {
+	icons := []Icon{
+		{URL: "16", Sizes: []Size{{16, 16}}},
+		{URL: "32", Sizes: []Size{{32, 32}}},
+		{URL: "svg", Scalable: true},
+		{URL: "180", Rel: RelAppleTouch, Sizes: []Size{{180, 180}}},
+		{URL: "multi", Sizes: []Size{{48, 48}, {64, 64}}},
+		{URL: "unknown"},
+		{URL: "json", Type: "application/json", Sizes: []Size{{32, 32}}},
+		{URL: "mask", Rel: RelMask, Scalable: true},
+		{URL: "mono", Rel: RelManifest, Sizes: []Size{{64, 64}}, Purpose: "monochrome"},
+		{URL: "ico", Rel: RelFavicon},
+	}
+
+	tests := map[string]struct {
+		size int
+		want []string
+	}{
+		"exact":        {32, []string{"32", "svg", "multi", "180", "unknown", "16", "mask", "mono", "ico"}},
+		"larger first": {40, []string{"svg", "multi", "180", "unknown", "32", "16", "mask", "mono", "ico"}},
+		"multi sizes":  {64, []string{"multi", "svg", "180", "unknown", "32", "16", "mask", "mono", "ico"}},
+		"huge":         {512, []string{"svg", "unknown", "180", "multi", "32", "16", "mask", "mono", "ico"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, i := range Rank(icons, tc.size) {
+				got = append(got, i.URL)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseSizes(t *testing.T) {
+	sizes, scalable := parseSizes(" 16x16 32X32 any 0x0 big 48x ")
+	if want := []Size{{16, 16}, {32, 32}}; !reflect.DeepEqual(sizes, want) || !scalable {
+		t.Fatalf("want %v and scalable, got %v and %v", want, sizes, scalable)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package jf2 provides conversion of microformats2 data to JF2, see
+// https://www.w3.org/TR/jf2/
+package jf2
+
+import (
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	mf "willnorris.com/go/microformats"
+)
+
+// Item represents a JF2 object
+type Item map[string]interface{}
+
+// referenced are the properties whose embedded objects are moved to the
+// references and replaced with their URLs
+var referenced = map[string]bool{
+	"in-reply-to":  true,
+	"like-of":      true,
+	"repost-of":    true,
+	"bookmark-of":  true,
+	"quotation-of": true,
+}
+
+// FromMicroformat returns the JF2 representation of the microformat
+func FromMicroformat(m *mf.Microformat) Item {
+	refs := map[string]Item{}
+	item := convert(m, refs)
+	if len(refs) > 0 {
+		item["references"] = refs
+	}
+	return item
+}
+
+// FromData returns the JF2 representation of the parsed page: the single
+// top-level item, or an object with all the top-level items as children.
+func FromData(d *mf.Data) Item {
+	if len(d.Items) == 1 {
+		return FromMicroformat(d.Items[0])
+	}
+
+	refs := map[string]Item{}
+	children := []Item{}
+	for _, i := range d.Items {
+		children = append(children, convert(i, refs))
+	}
+
+	item := Item{"children": children}
+	if len(refs) > 0 {
+		item["references"] = refs
+	}
+	return item
+}
+
+// FromHCard returns the JF2 representation of the h-card, with the values as
+// normalized by hcard (e.g. email addresses as mailto: URIs)
+func FromHCard(hc *hcard.HCard) Item {
+	item := Item{"type": "card"}
+	set(item, "name", hc.PName)
+	set(item, "nickname", hc.Nickname)
+	set(item, "note", hc.Note)
+
+	var photos []interface{}
+	for _, p := range hc.Photos {
+		if p.Alt == "" {
+			photos = append(photos, p.Value)
+		} else {
+			photos = append(photos, Item{"value": p.Value, "alt": p.Alt})
+		}
+	}
+	set(item, "photo", photos...)
+
+	set(item, "url", values(hc.URL)...)
+	set(item, "uid", values(hc.UID)...)
+	set(item, "email", values(hc.Email)...)
+	set(item, "tel", values(hc.Tel)...)
+
+	var orgs []interface{}
+	for _, o := range hc.Org {
+		if o.PName != "" && len(o.URL) == 0 && len(o.Photos) == 0 {
+			orgs = append(orgs, o.PName)
+		} else {
+			orgs = append(orgs, FromHCard(o))
+		}
+	}
+	set(item, "org", orgs...)
+
+	set(item, "job-title", values(hc.JobTitle)...)
+	set(item, "locality", hc.Locality)
+	set(item, "region", hc.Region)
+	set(item, "country-name", hc.CountryName)
+
+	var adrs []interface{}
+	for _, a := range hc.Adr {
+		if a == (hcard.Adr{Label: a.Label}) {
+			adrs = append(adrs, a.Label)
+			continue
+		}
+		adr := Item{"type": "adr"}
+		set(adr, "label", a.Label)
+		set(adr, "street-address", a.StreetAddress)
+		set(adr, "locality", a.Locality)
+		set(adr, "region", a.Region)
+		set(adr, "postal-code", a.PostalCode)
+		set(adr, "country-name", a.CountryName)
+		adrs = append(adrs, adr)
+	}
+	set(item, "adr", adrs...)
+
+	set(item, "bday", hc.Bday)
+	set(item, "pronouns", values(hc.Pronouns)...)
+	set(item, "category", values(hc.Category)...)
+	set(item, "key", values(hc.Key)...)
+	return item
+}
+
+func convert(m *mf.Microformat, refs map[string]Item) Item {
+	item := Item{}
+	if len(m.Type) > 0 {
+		item["type"] = strings.TrimPrefix(m.Type[0], "h-")
+	}
+
+	for name, values := range m.Properties {
+		var vv []interface{}
+		for _, v := range values {
+			vv = append(vv, value(name, v, refs))
+		}
+		switch len(vv) {
+		case 0:
+		case 1:
+			item[name] = vv[0]
+		default:
+			item[name] = vv
+		}
+	}
+
+	if len(m.Children) > 0 {
+		children := []Item{}
+		for _, c := range m.Children {
+			children = append(children, convert(c, refs))
+		}
+		item["children"] = children
+	}
+
+	return item
+}
+
+func value(name string, v interface{}, refs map[string]Item) interface{} {
+	switch v := v.(type) {
+	case map[string]string:
+		if html, ok := v["html"]; ok {
+			return Item{"html": html, "text": v["value"]}
+		}
+		if v["alt"] == "" {
+			return v["value"]
+		}
+		return Item{"value": v["value"], "alt": v["alt"]}
+	case *mf.Microformat:
+		nested := convert(v, refs)
+		if !referenced[name] {
+			return nested
+		}
+		u, ok := nested["url"].(string)
+		if !ok || u == "" {
+			return nested
+		}
+		refs[u] = nested
+		return u
+	}
+	return v
+}
+
+// set sets the property of the item to the non-empty values given: a single
+// value as is, several values as an array
+func set(item Item, name string, vv ...interface{}) {
+	var nonEmpty []interface{}
+	for _, v := range vv {
+		if s, ok := v.(string); !ok || s != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+
+	switch len(nonEmpty) {
+	case 0:
+	case 1:
+		item[name] = nonEmpty[0]
+	default:
+		item[name] = nonEmpty
+	}
+}
+
+func values(ss []string) []interface{} {
+	vv := make([]interface{}, len(ss))
+	for i, s := range ss {
+		vv[i] = s
+	}
+	return vv
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package jf2
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	mf "willnorris.com/go/microformats"
+)
+
+func TestFromData(t *testing.T) {
+	tests := map[string]struct {
+		html string
+		want string
+	}{
+		"card": {
+			`<a class="h-card" href="/">Jane</a>`,
+			`{"name":"Jane","type":"card","url":"https://example.com/"}`,
+		},
+		"photo with alt": {
+			`<div class="h-card"><img class="u-photo" src="/me.jpg" alt="me"><span class="p-name">Jane</span></div>`,
+			`{"name":"Jane","photo":{"alt":"me","value":"https://example.com/me.jpg"},"type":"card"}`,
+		},
+		"multiple values": {
+			`<div class="h-entry"><span class="p-category">a</span><span class="p-category">b</span></div>`,
+			`{"category":["a","b"],"type":"entry"}`,
+		},
+		"content and author": {
+			`<div class="h-entry"><div class="e-content"><b>Hi</b></div><a class="p-author h-card" href="/">Jane</a></div>`,
+			`{"author":{"name":"Jane","type":"card","url":"https://example.com/"},"content":{"html":"\u003cb\u003eHi\u003c/b\u003e","text":"Hi"},"type":"entry"}`,
+		},
+		"references": {
+			`<div class="h-entry"><p class="p-name">Nice</p><a class="u-in-reply-to h-cite" href="https://other.example/post">` +
+				`<span class="p-name">Original</span></a></div>`,
+			`{"in-reply-to":"https://other.example/post","name":"Nice",` +
+				`"references":{"https://other.example/post":{"name":"Original","type":"cite","url":"https://other.example/post"}},"type":"entry"}`,
+		},
+		"children": {
+			`<div class="h-feed"><p class="p-name">Feed</p><div class="h-entry"><p class="p-name">One</p></div></div>`,
+			`{"children":[{"name":"One","type":"entry"}],"name":"Feed","type":"feed"}`,
+		},
+		"top-level items": {
+			`<p class="h-entry"><span class="p-name">One</span></p><p class="h-entry"><span class="p-name">Two</span></p>`,
+			`{"children":[{"name":"One","type":"entry"},{"name":"Two","type":"entry"}]}`,
+		},
+	}
+
+	base, _ := url.Parse("https://example.com/page")
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := mf.Parse(strings.NewReader(tc.html), base)
+			got, err := json.Marshal(FromData(d))
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if string(got) != tc.want {
+				t.Fatalf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFromHCard(t *testing.T) {
+	hc := &hcard.HCard{
+		PName:  "Jane Doe",
+		Photos: []hcard.Photo{{Value: "https://example.com/me.jpg", Alt: "me"}},
+		URL:    []string{"https://example.com/", "https://social.example/@jane"},
+		Email:  []string{"mailto:jane@example.com"},
+		Org:    []*hcard.HCard{{PName: "ACME"}},
+		Adr:    []hcard.Adr{{Locality: "Springfield", CountryName: "US"}},
+	}
+	want := `{"adr":{"country-name":"US","locality":"Springfield","type":"adr"},"email":"mailto:jane@example.com","name":"Jane Doe",` +
+		`"org":"ACME","photo":{"alt":"me","value":"https://example.com/me.jpg"},"type":"card",` +
+		`"url":["https://example.com/","https://social.example/@jane"]}`
+
+	got, err := json.Marshal(FromHCard(hc))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("want %s, got %s", want, got)
+	}
+}
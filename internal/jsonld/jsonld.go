@@ -0,0 +1,593 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package jsonld provides handling for schema.org metadata embedded in
+// pages as JSON-LD, see https://json-ld.org/ and https://schema.org/
+package jsonld
+
+import (
+	"encoding/json"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hevent"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hproduct"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/strs"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ContentType is the media type of JSON-LD
+const ContentType = "application/ld+json"
+
+// The schema.org types mapped, with the subtypes commonly used
+var (
+	articleTypes = []string{
+		"Article", "BlogPosting", "NewsArticle", "TechArticle", "ScholarlyArticle", "Report",
+		"SocialMediaPosting", "DiscussionForumPosting", "LiveBlogPosting", "OpinionNewsArticle",
+	}
+	organizationTypes = []string{
+		"Organization", "Corporation", "LocalBusiness", "NewsMediaOrganization",
+		"EducationalOrganization", "GovernmentOrganization", "NGO", "OnlineBusiness",
+	}
+	eventTypes = []string{
+		"Event", "BusinessEvent", "ChildrensEvent", "ComedyEvent", "DanceEvent", "EducationEvent",
+		"ExhibitionEvent", "Festival", "FoodEvent", "Hackathon", "LiteraryEvent", "MusicEvent",
+		"SaleEvent", "ScreeningEvent", "SocialEvent", "SportsEvent", "TheaterEvent", "VisualArtsEvent",
+	}
+	productTypes = []string{"Product", "IndividualProduct", "ProductModel", "SomeProducts"}
+)
+
+// identifiers are the Product properties holding its identifiers
+var identifiers = []string{"sku", "gtin", "gtin8", "gtin12", "gtin13", "gtin14", "mpn", "isbn", "productID"}
+
+// Node represents a JSON-LD node object
+type Node map[string]interface{}
+
+// Data represents the JSON-LD data of a document
+type Data struct {
+	// Nodes are the top-level nodes, with @graph arrays flattened
+	Nodes []Node
+
+	nested []Node
+	ids    map[string]Node
+	base   *url.URL
+	source string
+}
+
+// Article represents a schema.org Article
+type Article struct {
+	Source      string       `json:"source,omitempty"`
+	Headline    string       `json:"headline,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Body        string       `json:"body,omitempty"`
+	Published   string       `json:"published,omitempty"`
+	Modified    string       `json:"modified,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Keywords    []string     `json:"keywords,omitempty"`
+	Image       []string     `json:"image,omitempty"`
+	Author      *hcard.HCard `json:"author,omitempty"`
+}
+
+// FromDocument returns the JSON-LD data of a document retrieved from the
+// given URL. Scripts that aren't valid JSON are skipped.
+func FromDocument(d *goquery.Document, u *url.URL) *Data {
+	data := &Data{ids: map[string]Node{}, base: urlnorm.Base(d, u)}
+	if u != nil {
+		data.source = u.String()
+	}
+
+	d.Find("script[type]").Each(func(_ int, s *goquery.Selection) {
+		t, _ := s.Attr("type")
+		if mt, _, err := mime.ParseMediaType(t); err != nil || mt != ContentType {
+			return
+		}
+
+		var v interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &v); err != nil {
+			return
+		}
+		data.add(v, true)
+	})
+
+	return data
+}
+
+// add adds the nodes found in the JSON value to the data, indexing the
+// nodes with @id so that references to them can be followed
+func (data *Data) add(v interface{}, top bool) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, i := range v {
+			data.add(i, top)
+		}
+	case map[string]interface{}:
+		n := Node(v)
+		if g, ok := n["@graph"]; ok {
+			data.add(g, top)
+			return
+		}
+		if _, ok := n["@value"]; ok {
+			return
+		}
+
+		if top {
+			data.Nodes = append(data.Nodes, n)
+		} else if len(n.Types()) > 0 {
+			data.nested = append(data.nested, n)
+		}
+
+		if id, ok := n["@id"].(string); ok && len(n) > 1 {
+			if known, ok := data.ids[id]; ok {
+				// the node is described in several places, merge them
+				for k, p := range n {
+					if _, ok := known[k]; !ok {
+						known[k] = p
+					}
+				}
+			} else {
+				data.ids[id] = Node{}
+				for k, p := range n {
+					data.ids[id][k] = p
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			if !strings.HasPrefix(k, "@") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			data.add(n[k], false)
+		}
+	}
+}
+
+// Types returns the schema.org types of the node, without the vocabulary
+// prefix
+func (n Node) Types() []string {
+	var types []string
+	for _, t := range values(n["@type"]) {
+		if s, ok := t.(string); ok {
+			s = strings.TrimPrefix(s, "schema:")
+			if i := strings.LastIndex(s, "/"); i >= 0 {
+				s = s[i+1:]
+			}
+			types = append(types, s)
+		}
+	}
+	return types
+}
+
+// Is reports whether the node is of any of the types
+func (n Node) Is(types ...string) bool {
+	for _, t := range n.Types() {
+		for _, want := range types {
+			if t == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Find returns the nodes of any of the types, the top-level ones first
+func (data *Data) Find(types ...string) []Node {
+	var found []Node
+	for _, nn := range [][]Node{data.Nodes, data.nested} {
+		for _, n := range nn {
+			if n = data.resolve(n); n.Is(types...) {
+				found = append(found, n)
+			}
+		}
+	}
+	return found
+}
+
+// Article returns the first Article (or one of its subtypes, e.g.
+// BlogPosting)
+func (data *Data) Article() *Article {
+	nn := data.Find(articleTypes...)
+	if len(nn) == 0 {
+		return nil
+	}
+	n := nn[0]
+
+	a := &Article{
+		Source:      data.source,
+		Headline:    text(n, "headline"),
+		Description: text(n, "description"),
+		Body:        text(n, "articleBody"),
+		Published:   text(n, "datePublished"),
+		Modified:    text(n, "dateModified"),
+		URL:         first(data.urls(n, "url")),
+		Keywords:    keywords(n),
+		Image:       data.urls(n, "image"),
+		Author:      data.card(n, "author"),
+	}
+	if a.Headline == "" {
+		a.Headline = text(n, "name")
+	}
+	if a.URL == "" {
+		a.URL = first(data.urls(n, "mainEntityOfPage"))
+	}
+	return a
+}
+
+// Person returns the Person representing the page retrieved from the given
+// URL as an HCard: the one with url matching the page URL, or the main
+// entity of a ProfilePage.
+func (data *Data) Person(u *url.URL) *hcard.HCard {
+	var page string
+	if u != nil {
+		page = u.String()
+	}
+
+	var person Node
+	for _, n := range data.Find("Person") {
+		for _, link := range data.urls(n, "url") {
+			if person == nil && urlnorm.Equivalent(link, page) {
+				person = n
+			}
+		}
+	}
+
+	for _, p := range data.Find("ProfilePage") {
+		for _, prop := range []string{"mainEntity", "about"} {
+			if n := data.node(p[prop]); person == nil && n.Is("Person") {
+				person = n
+			}
+		}
+	}
+
+	if person == nil {
+		return nil
+	}
+	hc := data.cardFromNode(person)
+	hc.Check = hcard.CheckJSONLD
+	return hc
+}
+
+// Organization returns the first Organization (or one of its subtypes) as
+// an HCard
+func (data *Data) Organization() *hcard.HCard {
+	nn := data.Find(organizationTypes...)
+	if len(nn) == 0 {
+		return nil
+	}
+	return data.cardFromNode(nn[0])
+}
+
+// Event returns the first Event (or one of its subtypes) as an HEvent
+func (data *Data) Event() *hevent.HEvent {
+	nn := data.Find(eventTypes...)
+	if len(nn) == 0 {
+		return nil
+	}
+	n := nn[0]
+
+	return &hevent.HEvent{
+		Source:      data.source,
+		Name:        text(n, "name"),
+		Description: text(n, "description"),
+		Start:       text(n, "startDate"),
+		End:         text(n, "endDate"),
+		Duration:    text(n, "duration"),
+		Location:    data.location(n),
+		URL:         first(data.urls(n, "url")),
+		Category:    keywords(n),
+		Organizer:   data.card(n, "organizer"),
+	}
+}
+
+// Product returns the first Product (or one of its subtypes) as an HProduct
+func (data *Data) Product() *hproduct.HProduct {
+	nn := data.Find(productTypes...)
+	if len(nn) == 0 {
+		return nil
+	}
+	n := nn[0]
+
+	p := &hproduct.HProduct{
+		Source:      data.source,
+		Name:        text(n, "name"),
+		Price:       data.price(n),
+		Brand:       data.card(n, "brand"),
+		Photo:       data.urls(n, "image"),
+		Description: text(n, "description"),
+		URL:         first(data.urls(n, "url")),
+		Category:    texts(n, "category"),
+	}
+	for _, id := range identifiers {
+		p.Identifier = append(p.Identifier, texts(n, id)...)
+	}
+	return p
+}
+
+// card returns the first value of the property as an HCard: a node is
+// converted, a text is taken as the name
+func (data *Data) card(n Node, prop string) *hcard.HCard {
+	for _, v := range values(n[prop]) {
+		if s, ok := v.(string); ok && data.ids[s] == nil {
+			if s = strings.TrimSpace(s); s != "" {
+				return &hcard.HCard{PName: s}
+			}
+			continue
+		}
+		if c := data.node(v); c != nil {
+			return data.cardFromNode(c)
+		}
+	}
+	return nil
+}
+
+// cardFromNode returns the HCard describing the Person or Organization node
+func (data *Data) cardFromNode(n Node) *hcard.HCard {
+	hc := &hcard.HCard{
+		Source:   data.source,
+		PName:    text(n, "name"),
+		Nickname: text(n, "alternateName"),
+		Note:     text(n, "description"),
+		Tel:      texts(n, "telephone"),
+		JobTitle: texts(n, "jobTitle"),
+	}
+	if hc.PName == "" {
+		hc.PName = strings.TrimSpace(text(n, "givenName") + " " + text(n, "familyName"))
+	}
+
+	for _, link := range append(data.urls(n, "url"), data.urls(n, "sameAs")...) {
+		if !strs.Contains(hc.URL, link) {
+			hc.URL = append(hc.URL, link)
+		}
+	}
+
+	for _, e := range texts(n, "email") {
+		if !strings.HasPrefix(e, "mailto:") {
+			e = "mailto:" + e
+		}
+		hc.Email = append(hc.Email, e)
+	}
+
+	for _, p := range append(data.urls(n, "image"), data.urls(n, "logo")...) {
+		hc.Photos = append(hc.Photos, hcard.Photo{Value: p})
+	}
+	if len(hc.Photos) > 0 {
+		hc.Photo = hc.Photos[0].Value
+	}
+
+	for _, prop := range []string{"worksFor", "affiliation"} {
+		for _, v := range values(n[prop]) {
+			if s, ok := v.(string); ok && data.ids[s] == nil {
+				hc.Org = append(hc.Org, &hcard.HCard{PName: s})
+			} else if o := data.node(v); o != nil {
+				hc.Org = append(hc.Org, &hcard.HCard{PName: text(o, "name"), URL: data.urls(o, "url")})
+			}
+		}
+	}
+
+	for _, v := range values(n["address"]) {
+		if a := data.adr(v); a != nil {
+			hc.Adr = append(hc.Adr, *a)
+		}
+	}
+
+	return hc
+}
+
+// adr returns the address described by the PostalAddress node or the text
+func (data *Data) adr(v interface{}) *hcard.Adr {
+	if s, ok := v.(string); ok && data.ids[s] == nil {
+		if s = strings.TrimSpace(s); s != "" {
+			return &hcard.Adr{Label: s}
+		}
+		return nil
+	}
+
+	n := data.node(v)
+	if n == nil {
+		return nil
+	}
+	a := hcard.Adr{
+		StreetAddress: text(n, "streetAddress"),
+		Locality:      text(n, "addressLocality"),
+		Region:        text(n, "addressRegion"),
+		PostalCode:    text(n, "postalCode"),
+		CountryName:   text(n, "addressCountry"),
+	}
+	if a.CountryName == "" {
+		a.CountryName = text(data.node(n["addressCountry"]), "name")
+	}
+	if a == (hcard.Adr{}) {
+		return nil
+	}
+	return &a
+}
+
+// location returns the location of the event node
+func (data *Data) location(n Node) *hevent.Location {
+	for _, v := range values(n["location"]) {
+		if s, ok := v.(string); ok && data.ids[s] == nil {
+			if s = strings.TrimSpace(s); s != "" {
+				return &hevent.Location{Type: hevent.LocationText, Name: s}
+			}
+			continue
+		}
+
+		l := data.node(v)
+		switch {
+		case l.Is("PostalAddress"):
+			if a := data.adr(l); a != nil {
+				return &hevent.Location{Type: hevent.LocationAdr, Adr: a}
+			}
+		case l.Is("VirtualLocation"):
+			name := first(data.urls(l, "url"))
+			if name == "" {
+				name = text(l, "name")
+			}
+			if name != "" {
+				return &hevent.Location{Type: hevent.LocationText, Name: name}
+			}
+		case l != nil:
+			c := data.cardFromNode(l)
+			c.Source = ""
+			if c.PName != "" || len(c.Adr) > 0 {
+				return &hevent.Location{Type: hevent.LocationCard, Name: c.PName, Card: c}
+			}
+		}
+	}
+	return nil
+}
+
+// price returns the price of the first offer of the product node, together
+// with its currency
+func (data *Data) price(n Node) string {
+	for _, v := range values(n["offers"]) {
+		o := data.node(v)
+		p := text(o, "price")
+		if p == "" {
+			p = text(o, "lowPrice")
+		}
+		if p != "" {
+			return strings.TrimSpace(p + " " + text(o, "priceCurrency"))
+		}
+	}
+	return ""
+}
+
+// resolve returns the full description of the node if it is known by @id
+func (data *Data) resolve(n Node) Node {
+	if id, ok := n["@id"].(string); ok {
+		if known, ok := data.ids[id]; ok {
+			return known
+		}
+	}
+	return n
+}
+
+// node returns the value as a node, following references by @id
+func (data *Data) node(v interface{}) Node {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return data.resolve(Node(v))
+	case Node:
+		return data.resolve(v)
+	case string:
+		return data.ids[v]
+	}
+	return nil
+}
+
+// urls returns the URLs the property refers to, resolved against the base
+// of the document; nodes are referred to with their url, or with their @id
+// if they have no url
+func (data *Data) urls(n Node, prop string) []string {
+	var links []string
+	for _, v := range values(n[prop]) {
+		var link string
+		switch v := v.(type) {
+		case string:
+			link = v
+		case map[string]interface{}:
+			link = first(texts(v, "url"))
+			if link == "" {
+				link = first(texts(v, "contentUrl"))
+			}
+			if link == "" {
+				link, _ = v["@id"].(string)
+			}
+		}
+		if link = urlnorm.Resolve(data.base, link); link != "" {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// text returns the first text value of the property
+func text(n Node, prop string) string {
+	return first(texts(n, prop))
+}
+
+// texts returns the text values of the property, numbers included
+func texts(n Node, prop string) []string {
+	var ss []string
+	for _, v := range values(n[prop]) {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = m["@value"]
+		}
+
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = strings.TrimSpace(v)
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if s != "" {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// keywords returns the keywords of the node, splitting the comma-separated
+// text if that's how they are given
+func keywords(n Node) []string {
+	kw := texts(n, "keywords")
+	if len(kw) != 1 {
+		return kw
+	}
+
+	var split []string
+	for _, k := range strings.Split(kw[0], ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			split = append(split, k)
+		}
+	}
+	return split
+}
+
+// values returns the value as a slice, flattening arrays and @list objects
+func values(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var vv []interface{}
+		for _, i := range v {
+			vv = append(vv, values(i)...)
+		}
+		return vv
+	case map[string]interface{}:
+		if l, ok := v["@list"]; ok {
+			return values(l)
+		}
+	}
+	return []interface{}{v}
+}
+
+func first(ss []string) string {
+	if len(ss) == 0 {
+		return ""
+	}
+	return ss[0]
+}
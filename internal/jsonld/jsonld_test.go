@@ -0,0 +1,163 @@
+package jsonld
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hevent"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hproduct"
+	"github.com/PuerkitoBio/goquery"
+)
+
+var jane = &hcard.HCard{
+	Source:   "https://jane.example/fish/",
+	PName:    "Jane Doe",
+	Photo:    "https://jane.example/jane.jpg",
+	Photos:   []hcard.Photo{{Value: "https://jane.example/jane.jpg"}},
+	URL:      []string{"https://jane.example/", "https://social.example/@jane"},
+	Email:    []string{"mailto:jane@example.com"},
+	Org:      []*hcard.HCard{{PName: "ACME", URL: []string{"https://acme.example/"}}},
+	JobTitle: []string{"Cook"},
+}
+
+func TestArticle(t *testing.T) {
+	want := &Article{
+		Source:      "https://jane.example/fish/",
+		Headline:    "Fish and chips",
+		Description: "A tasty meal.",
+		Body:        "Take some fish. Take some chips.",
+		Published:   "2026-09-01T12:00:00+02:00",
+		Modified:    "2026-09-02T08:00:00+02:00",
+		URL:         "https://jane.example/fish/",
+		Keywords:    []string{"fish", "chips", "dinner"},
+		Image:       []string{"https://jane.example/img/fish.jpg"},
+		Author:      jane,
+	}
+
+	got := fromFile(t, "article.html", "https://jane.example/fish/").Article()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestPerson(t *testing.T) {
+	tests := map[string]struct {
+		filename string
+		link     string
+		want     *hcard.HCard
+	}{
+		"url matches": {"article.html", "https://jane.example/", &hcard.HCard{
+			Source:   "https://jane.example/",
+			Check:    hcard.CheckJSONLD,
+			PName:    "Jane Doe",
+			Photo:    "https://jane.example/jane.jpg",
+			Photos:   []hcard.Photo{{Value: "https://jane.example/jane.jpg"}},
+			URL:      []string{"https://jane.example/", "https://social.example/@jane"},
+			Email:    []string{"mailto:jane@example.com"},
+			Org:      []*hcard.HCard{{PName: "ACME", URL: []string{"https://acme.example/"}}},
+			JobTitle: []string{"Cook"},
+		}},
+		"profile page": {"profile.html", "https://john.example/about", &hcard.HCard{
+			Source: "https://john.example/about",
+			Check:  hcard.CheckJSONLD,
+			PName:  "John Smith",
+			URL:    []string{"https://social.example/@john"},
+			Adr:    []hcard.Adr{{Label: "Springfield"}},
+		}},
+		"author of a post": {"article.html", "https://jane.example/fish/", nil},
+		"no person":        {"event.html", "https://events.example/iwc", nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(tc.link)
+			got := fromFile(t, tc.filename, tc.link).Person(u)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOrganization(t *testing.T) {
+	want := &hcard.HCard{Source: "https://jane.example/fish/", PName: "ACME", URL: []string{"https://acme.example/"}}
+
+	got := fromFile(t, "article.html", "https://jane.example/fish/").Organization()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	want := &hevent.HEvent{
+		Source:      "https://events.example/iwc",
+		Name:        "IndieWebCamp",
+		Description: "Two days of building our own websites.",
+		Start:       "2026-11-05T09:00-07:00",
+		End:         "2026-11-06T17:00-07:00",
+		URL:         "https://events.example/iwc",
+		Location: &hevent.Location{Type: hevent.LocationCard, Name: "Springfield Library", Card: &hcard.HCard{
+			PName: "Springfield Library",
+			Adr:   []hcard.Adr{{StreetAddress: "1 Main St", Locality: "Springfield", CountryName: "US"}},
+		}},
+		Organizer: &hcard.HCard{PName: "Jane Doe"},
+	}
+
+	got := fromFile(t, "event.html", "https://events.example/iwc").Event()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestProduct(t *testing.T) {
+	want := &hproduct.HProduct{
+		Source:      "https://shop.example/widget",
+		Name:        "Widget",
+		Price:       "19.99 EUR",
+		Brand:       &hcard.HCard{Source: "https://shop.example/widget", PName: "ACME"},
+		Photo:       []string{"https://shop.example/widget.jpg", "https://shop.example/widget-2.jpg"},
+		Description: "A useful widget.",
+		Identifier:  []string{"W-1", "4006381333931"},
+		Category:    []string{"Tools"},
+	}
+
+	got := fromFile(t, "event.html", "https://shop.example/widget").Product()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestNoData(t *testing.T) {
+	d, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><script type="application/json">{"@type": "Person"}</script></head></html>`))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	data := FromDocument(d, nil)
+	if len(data.Nodes) != 0 || data.Article() != nil || data.Event() != nil || data.Product() != nil || data.Organization() != nil || data.Person(nil) != nil {
+		t.Fatalf("want no data, got %+v", data.Nodes)
+	}
+}
+
+func fromFile(t *testing.T, filename, link string) *Data {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return FromDocument(d, u)
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package manifest provides handling for web app manifests, see
+// https://www.w3.org/TR/appmanifest/
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxSize is the maximum size of the manifest read
+const maxSize = 1 << 20
+
+// Manifest represents a web app manifest
+type Manifest struct {
+	Source          string `json:"source,omitempty"`
+	Name            string `json:"name,omitempty"`
+	ShortName       string `json:"shortName,omitempty"`
+	ThemeColor      string `json:"themeColor,omitempty"`
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+	Icons           []Icon `json:"icons,omitempty"`
+}
+
+// Icon represents an icon listed in a web app manifest
+type Icon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// Fetch returns the web app manifest of the page at the given URL, together
+// with the header of the manifest response.
+func Fetch(link string) (*Manifest, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := URL(d, res.Request.URL)
+	if m == "" {
+		return nil, &res.Header, fmt.Errorf("no manifest found")
+	}
+	return Load(m)
+}
+
+// URL returns the URL of the web app manifest of a document retrieved from
+// the given URL, or the empty string if there is none.
+func URL(d *goquery.Document, u *url.URL) string {
+	var link string
+	d.Find("link[rel][href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		rel, _ := s.Attr("rel")
+		for _, r := range strings.Fields(strings.ToLower(rel)) {
+			if r == "manifest" {
+				href, _ := s.Attr("href")
+				link = urlnorm.Resolve(urlnorm.Base(d, u), href)
+				break
+			}
+		}
+		return link == ""
+	})
+	return link
+}
+
+// Load returns the web app manifest at the given URL, together with the
+// response header.
+func Load(link string) (*Manifest, *http.Header, error) {
+	res, err := http.Get(link)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &res.Header, fmt.Errorf("manifest returned %s", res.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxSize))
+	if err != nil {
+		return nil, &res.Header, err
+	}
+
+	m, err := Parse(b, res.Request.URL)
+	return m, &res.Header, err
+}
+
+// Parse returns the web app manifest retrieved from the given URL. Icon URLs
+// are resolved against the manifest URL, icons without valid absolute
+// http(s) URLs are dropped.
+func Parse(b []byte, u *url.URL) (*Manifest, error) {
+	var raw struct {
+		Name            string `json:"name"`
+		ShortName       string `json:"short_name"`
+		ThemeColor      string `json:"theme_color"`
+		BackgroundColor string `json:"background_color"`
+		Icons           []Icon `json:"icons"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	m := Manifest{
+		Name:            strings.TrimSpace(raw.Name),
+		ShortName:       strings.TrimSpace(raw.ShortName),
+		ThemeColor:      strings.TrimSpace(raw.ThemeColor),
+		BackgroundColor: strings.TrimSpace(raw.BackgroundColor),
+	}
+	if u != nil {
+		m.Source = u.String()
+	}
+
+	for _, i := range raw.Icons {
+		i.Src = urlnorm.Resolve(u, i.Src)
+		if i.Src == "" {
+			continue
+		}
+		i.Sizes = strings.TrimSpace(i.Sizes)
+		i.Type = strings.ToLower(strings.TrimSpace(i.Type))
+		i.Purpose = strings.ToLower(strings.TrimSpace(i.Purpose))
+		m.Icons = append(m.Icons, i)
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	pages := map[string]string{
+		"/":         `<link rel="manifest" href="/app/manifest.json">`,
+		"/base":     `<base href="/app/"><link rel="Manifest" href="manifest.json">`,
+		"/broken":   `<link rel="manifest" href="/broken.json">`,
+		"/missing":  `<link rel="manifest" href="/missing.json">`,
+		"/none":     `<link rel="icon" href="/favicon.ico">`,
+		"/app/page": `<link rel="manifest" href="manifest.json">`,
+	}
+
+	mux := http.NewServeMux()
+	for p, body := range pages {
+		p, body := p, body
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != p {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, "<!DOCTYPE html><html><head>%s</head><body></body></html>", body)
+		})
+	}
+	mux.HandleFunc("/app/manifest.json", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"name":" Jane's kitchen ","short_name":"Kitchen","theme_color":"#336699","background_color":"white",`+
+			`"icons":[{"src":"icon-192.png","sizes":"192x192","type":"IMAGE/PNG"},{"src":"javascript:void(0)"},`+
+			`{"src":"/mono.svg","sizes":"any","purpose":"Monochrome"}],"display":"standalone"}`)
+	})
+	mux.HandleFunc("/broken.json", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"name":`)
+	})
+
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	want := &Manifest{
+		Source:          s.URL + "/app/manifest.json",
+		Name:            "Jane's kitchen",
+		ShortName:       "Kitchen",
+		ThemeColor:      "#336699",
+		BackgroundColor: "white",
+		Icons: []Icon{
+			{Src: s.URL + "/app/icon-192.png", Sizes: "192x192", Type: "image/png"},
+			{Src: s.URL + "/mono.svg", Sizes: "any", Purpose: "monochrome"},
+		},
+	}
+
+	tests := map[string]struct {
+		link string
+		want *Manifest
+	}{
+		"manifest":         {"/", want},
+		"base":             {"/base", want},
+		"relative":         {"/app/page", want},
+		"broken manifest":  {"/broken", nil},
+		"missing manifest": {"/missing", nil},
+		"no manifest":      {"/none", nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _, err := Fetch(s.URL + tc.link)
+			if tc.want == nil {
+				if err == nil {
+					t.Fatalf("want error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	u, _ := url.Parse("https://example.com/manifest.json")
+	m, err := Parse([]byte(`{"icons":"not a list"}`), u)
+	if err == nil {
+		t.Fatalf("want error, got %+v", m)
+	}
+
+	m, err = Parse([]byte(`{}`), u)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if want := (&Manifest{Source: "https://example.com/manifest.json"}); !reflect.DeepEqual(m, want) {
+		t.Fatalf("want %+v, got %+v", want, m)
+	}
+}
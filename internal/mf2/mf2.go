@@ -0,0 +1,182 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package mf2 provides handling for raw microformats2 data.
+package mf2
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	mf "willnorris.com/go/microformats"
+)
+
+// Fetch fetches the page at URI and returns the parsed microformats2 data,
+// together with the response header. Relative URLs are resolved against the
+// final URL of the page, or the <base> of the document if there is one.
+func Fetch(uri string) (*mf.Data, *http.Header, error) {
+	d, _, hd, err := fetch(uri)
+	return d, hd, err
+}
+
+// FetchPrimary fetches the page at URI and returns its primary microformat
+// of type t, together with the final URL of the page and the response
+// header.
+func FetchPrimary(uri, t string) (*mf.Microformat, *url.URL, *http.Header, error) {
+	d, u, hd, err := fetch(uri)
+	if err != nil {
+		return nil, nil, hd, err
+	}
+
+	i := Primary(d, u, t)
+	if i == nil {
+		return nil, u, hd, fmt.Errorf("no %s found", t)
+	}
+	return i, u, hd, nil
+}
+
+// fetch fetches and parses the page at URI, returning the final URL of the
+// page as well
+func fetch(uri string) (*mf.Data, *url.URL, *http.Header, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d := mf.Parse(res.Body, res.Request.URL)
+	return d, res.Request.URL, &res.Header, nil
+}
+
+// HasType reports whether the microformat is of type t
+func HasType(m *mf.Microformat, t string) bool {
+	if m == nil {
+		return false
+	}
+	for _, v := range m.Type {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns all the microformats of type t among items and their
+// children, in document order
+func Find(items []*mf.Microformat, t string) (found []*mf.Microformat) {
+	for _, i := range items {
+		if HasType(i, t) {
+			found = append(found, i)
+		}
+		found = append(found, Find(i.Children, t)...)
+	}
+	return
+}
+
+// Primary returns the primary microformat of type t of the parsed page
+// retrieved from the given URL: the one with url matching the page URL if
+// there is one, the first one on the page otherwise.
+func Primary(d *mf.Data, u *url.URL, t string) *mf.Microformat {
+	if d == nil {
+		return nil
+	}
+
+	found := Find(d.Items, t)
+	if len(found) == 0 {
+		return nil
+	}
+
+	for _, m := range found {
+		for _, link := range Properties(m, "url") {
+			if urlnorm.Equivalent(link, u.String()) {
+				return m
+			}
+		}
+	}
+
+	return found[0]
+}
+
+// Property returns the first value of the property as a string
+func Property(m *mf.Microformat, property string) string {
+	if m == nil || len(m.Properties[property]) < 1 {
+		return ""
+	}
+	return Value(m.Properties[property][0])
+}
+
+// Properties returns all the values of the property as strings
+func Properties(m *mf.Microformat, property string) (values []string) {
+	if m == nil {
+		return
+	}
+	for _, v := range m.Properties[property] {
+		if s := Value(v); s != "" {
+			values = append(values, s)
+		}
+	}
+	return
+}
+
+// HTML returns the HTML of the first value of an embedded markup property
+func HTML(m *mf.Microformat, property string) string {
+	if m == nil || len(m.Properties[property]) < 1 {
+		return ""
+	}
+
+	switch v := m.Properties[property][0].(type) {
+	case map[string]string:
+		return v["html"]
+	case *mf.Microformat:
+		return v.HTML
+	}
+	return ""
+}
+
+// Embedded returns the first value of the property if it is a nested
+// microformat
+func Embedded(m *mf.Microformat, property string) *mf.Microformat {
+	if m == nil || len(m.Properties[property]) < 1 {
+		return nil
+	}
+	if v, ok := m.Properties[property][0].(*mf.Microformat); ok {
+		return v
+	}
+	return nil
+}
+
+// Value returns the plain text value of a single property value
+func Value(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]string:
+		return v["value"]
+	case *mf.Microformat:
+		return v.Value
+	}
+	return ""
+}
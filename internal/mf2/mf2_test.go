@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package mf2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	d, _, err := Fetch(s.URL + "/base.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if len(d.Items) != 1 {
+		t.Fatalf("want 1 item, got %d", len(d.Items))
+	}
+	e := d.Items[0]
+
+	tests := map[string]struct {
+		got  string
+		want string
+	}{
+		"url":          {Property(e, "url"), s.URL + "/blog/2023/hello/"},
+		"author photo": {Property(Embedded(e, "author"), "photo"), s.URL + "/me.jpg"},
+		"content html": {HTML(e, "content"), `<p>Hi <a href="` + s.URL + `/blog/about/">there</a>.</p>`},
+		"embedded":     {Property(e, "like-of"), "https://example.com/"},
+		"rel":          {d.Rels["stylesheet"][0], s.URL + "/blog/style.css"},
+		"rel-url":      {d.RelURLs["https://github.com/jane"].Rels[0], "me"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestFetchPrimary(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	i, u, _, err := FetchPrimary(s.URL+"/base.html", "h-entry")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if u.String() != s.URL+"/base.html" {
+		t.Fatalf("want URL %q, got %q", s.URL+"/base.html", u)
+	}
+	if !HasType(i, "h-entry") {
+		t.Fatalf("want h-entry, got %v", i.Type)
+	}
+
+	if i, _, _, err := FetchPrimary(s.URL+"/base.html", "h-event"); err == nil {
+		t.Fatalf("want error, got %v", i)
+	}
+}
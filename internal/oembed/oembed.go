@@ -0,0 +1,309 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package oembed provides handling for oEmbed, see https://oembed.com/
+package oembed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/strs"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// The media types of oEmbed responses
+const (
+	JSONType = "application/json+oembed"
+	XMLType  = "text/xml+oembed"
+)
+
+// Version is the oEmbed version supported
+const Version = "1.0"
+
+// maxResponseSize is the maximum size of the provider response read
+const maxResponseSize = 1 << 20
+
+// Response represents an oEmbed response
+type Response struct {
+	XMLName         xml.Name `json:"-" xml:"oembed"`
+	Type            string   `json:"type" xml:"type"`
+	Version         string   `json:"version" xml:"version"`
+	Title           string   `json:"title,omitempty" xml:"title,omitempty"`
+	AuthorName      string   `json:"author_name,omitempty" xml:"author_name,omitempty"`
+	AuthorURL       string   `json:"author_url,omitempty" xml:"author_url,omitempty"`
+	ProviderName    string   `json:"provider_name,omitempty" xml:"provider_name,omitempty"`
+	ProviderURL     string   `json:"provider_url,omitempty" xml:"provider_url,omitempty"`
+	CacheAge        int      `json:"cache_age,omitempty" xml:"cache_age,omitempty"`
+	ThumbnailURL    string   `json:"thumbnail_url,omitempty" xml:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int      `json:"thumbnail_width,omitempty" xml:"thumbnail_width,omitempty"`
+	ThumbnailHeight int      `json:"thumbnail_height,omitempty" xml:"thumbnail_height,omitempty"`
+	URL             string   `json:"url,omitempty" xml:"url,omitempty"`
+	HTML            string   `json:"html,omitempty" xml:"html,omitempty"`
+	Width           int      `json:"width,omitempty" xml:"width,omitempty"`
+	Height          int      `json:"height,omitempty" xml:"height,omitempty"`
+}
+
+// Embed represents the oEmbed response of a third-party provider for a
+// page. The HTML the provider returned is kept apart from the response,
+// since it is not to be trusted.
+type Embed struct {
+	Source   string `json:"source"`
+	Endpoint string `json:"endpoint"`
+	Response
+	UntrustedHTML string `json:"untrusted_html,omitempty"`
+}
+
+// Fetch discovers the oEmbed endpoint of the page at the given URL and
+// returns the embed it provides, passing maxWidth and maxHeight (if not 0)
+// to the provider. The header returned is the one of the provider response.
+func Fetch(link string, maxWidth, maxHeight int) (*Embed, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoint := Discover(d, res.Request.URL)
+	if endpoint == "" {
+		return nil, nil, fmt.Errorf("no oEmbed endpoint found")
+	}
+
+	endpoint, err = withSize(endpoint, maxWidth, maxHeight)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, hd, err := fetchResponse(endpoint)
+	if err != nil {
+		return nil, hd, err
+	}
+
+	r.fit(maxWidth, maxHeight)
+	e := &Embed{Source: res.Request.URL.String(), Endpoint: endpoint, Response: *r, UntrustedHTML: r.HTML}
+	e.Response.HTML = ""
+	return e, hd, nil
+}
+
+// Discover returns the oEmbed endpoint advertised by a document retrieved
+// from the given URL, JSON preferred over XML, or the empty string if there
+// is none.
+func Discover(d *goquery.Document, u *url.URL) string {
+	base := urlnorm.Base(d, u)
+	for _, t := range []string{JSONType, XMLType} {
+		var endpoint string
+		d.Find("link[rel][type][href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			rel, _ := s.Attr("rel")
+			typ, _ := s.Attr("type")
+			if !strs.Contains(strings.Fields(strings.ToLower(rel)), "alternate") || !strings.EqualFold(strings.TrimSpace(typ), t) {
+				return true
+			}
+			href, _ := s.Attr("href")
+			endpoint = urlnorm.Resolve(base, href)
+			return endpoint == ""
+		})
+		if endpoint != "" {
+			return endpoint
+		}
+	}
+	return ""
+}
+
+// withSize returns the endpoint URL with maxwidth and maxheight parameters
+// set
+func withSize(endpoint string, maxWidth, maxHeight int) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	if maxWidth > 0 {
+		q.Set("maxwidth", strconv.Itoa(maxWidth))
+	}
+	if maxHeight > 0 {
+		q.Set("maxheight", strconv.Itoa(maxHeight))
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// fetchResponse fetches and validates the oEmbed response at the endpoint
+func fetchResponse(endpoint string) (*Response, *http.Header, error) {
+	res, err := http.Get(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &res.Header, fmt.Errorf("oEmbed provider returned %s", res.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, &res.Header, err
+	}
+
+	var fields map[string]string
+	if strings.HasPrefix(strings.TrimSpace(string(b)), "<") {
+		fields, err = parseXML(b)
+	} else {
+		fields, err = parseJSON(b)
+	}
+	if err != nil {
+		return nil, &res.Header, err
+	}
+
+	r, err := FromFields(fields)
+	return r, &res.Header, err
+}
+
+// parseJSON returns the fields of a JSON oEmbed response as strings
+func parseJSON(b []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+		case float64:
+			fields[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return fields, nil
+}
+
+// parseXML returns the fields of an XML oEmbed response
+func parseXML(b []byte) (map[string]string, error) {
+	var raw struct {
+		XMLName xml.Name
+		Fields  []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := xml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	if raw.XMLName.Local != "oembed" {
+		return nil, fmt.Errorf("not an oEmbed response")
+	}
+
+	fields := map[string]string{}
+	for _, f := range raw.Fields {
+		fields[f.XMLName.Local] = strings.TrimSpace(f.Value)
+	}
+	return fields, nil
+}
+
+// FromFields returns the Response described by the fields of an oEmbed
+// response, if it is a valid one: the version is 1.0, the type is known,
+// and the parameters required for the type are present. URLs that aren't
+// valid absolute http(s) URLs are dropped.
+func FromFields(fields map[string]string) (*Response, error) {
+	if v, err := strconv.ParseFloat(fields["version"], 64); err != nil || v != 1 {
+		return nil, fmt.Errorf("unsupported oEmbed version %q", fields["version"])
+	}
+
+	r := Response{
+		Type:            fields["type"],
+		Version:         Version,
+		Title:           fields["title"],
+		AuthorName:      fields["author_name"],
+		AuthorURL:       urlnorm.Resolve(nil, fields["author_url"]),
+		ProviderName:    fields["provider_name"],
+		ProviderURL:     urlnorm.Resolve(nil, fields["provider_url"]),
+		CacheAge:        number(fields["cache_age"]),
+		ThumbnailURL:    urlnorm.Resolve(nil, fields["thumbnail_url"]),
+		ThumbnailWidth:  number(fields["thumbnail_width"]),
+		ThumbnailHeight: number(fields["thumbnail_height"]),
+		URL:             urlnorm.Resolve(nil, fields["url"]),
+		HTML:            fields["html"],
+		Width:           number(fields["width"]),
+		Height:          number(fields["height"]),
+	}
+
+	switch r.Type {
+	case "photo":
+		if r.URL == "" || r.Width == 0 || r.Height == 0 {
+			return nil, fmt.Errorf("photo oEmbed response without url, width or height")
+		}
+		r.HTML = ""
+	case "video", "rich":
+		if r.HTML == "" || r.Width == 0 || r.Height == 0 {
+			return nil, fmt.Errorf("%s oEmbed response without html, width or height", r.Type)
+		}
+		r.URL = ""
+	case "link":
+		r.URL, r.HTML = "", ""
+	default:
+		return nil, fmt.Errorf("unknown oEmbed response type %q", r.Type)
+	}
+
+	if r.ThumbnailURL == "" || r.ThumbnailWidth == 0 || r.ThumbnailHeight == 0 {
+		// the thumbnail parameters must be present all together
+		r.ThumbnailURL, r.ThumbnailWidth, r.ThumbnailHeight = "", 0, 0
+	}
+
+	return &r, nil
+}
+
+// fit drops the parts of the response that don't fit within maxWidth and
+// maxHeight (if not 0), since the provider isn't bound to respect them: the
+// thumbnail, and the photo or HTML, leaving a link response
+func (r *Response) fit(maxWidth, maxHeight int) {
+	if !fits(r.ThumbnailWidth, maxWidth) || !fits(r.ThumbnailHeight, maxHeight) {
+		r.ThumbnailURL, r.ThumbnailWidth, r.ThumbnailHeight = "", 0, 0
+	}
+	if r.Type != "link" && (!fits(r.Width, maxWidth) || !fits(r.Height, maxHeight)) {
+		r.Type = "link"
+		r.URL, r.HTML, r.Width, r.Height = "", "", 0, 0
+	}
+}
+
+// number returns the positive integer value of the field, or 0
+func number(s string) int {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return int(f)
+}
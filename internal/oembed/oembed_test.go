@@ -0,0 +1,182 @@
+package oembed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestFetch(t *testing.T) {
+	pages := map[string]string{
+		"/video":    `<link rel="alternate" type="application/json+oembed" href="/oembed/video?format=json&maxwidth=100">`,
+		"/xml":      `<link rel="alternate" type="text/xml+oembed" href="/oembed/photo.xml"><link rel="alternate" type="application/json+oembed" href="/oembed/link">`,
+		"/only-xml": `<base href="/oembed/"><link rel="Alternate" type="text/xml+oembed" href="photo.xml">`,
+		"/invalid":  `<link rel="alternate" type="application/json+oembed" href="/oembed/invalid">`,
+		"/missing":  `<link rel="alternate" type="application/json+oembed" href="/oembed/missing">`,
+		"/none":     `<link rel="alternate" type="application/rss+xml" href="/feed">`,
+	}
+	responses := map[string]string{
+		"/oembed/video": `{"version":"1.0","type":"video","title":"A video","author_name":"Jane","author_url":"https://jane.example/",` +
+			`"width":"640","height":360,"html":"<iframe src=\"https://player.example/1\"></iframe>","url":"https://ignored.example/",` +
+			`"thumbnail_url":"https://player.example/1.jpg","thumbnail_width":320,"thumbnail_height":180,"cache_age":3600}`,
+		"/oembed/photo.xml": `<?xml version="1.0" encoding="utf-8"?><oembed><version>1.0</version><type>photo</type>` +
+			`<title>A photo</title><url>https://photos.example/1.jpg</url><width>800</width><height>600</height>` +
+			`<thumbnail_url>javascript:alert(1)</thumbnail_url><thumbnail_width>80</thumbnail_width><thumbnail_height>60</thumbnail_height></oembed>`,
+		"/oembed/link":    `{"version":"1.0","type":"link","title":"A link"}`,
+		"/oembed/invalid": `{"version":"1.0","type":"rich","width":100,"height":100}`,
+	}
+
+	var queries []string
+	mux := http.NewServeMux()
+	for p, body := range pages {
+		body := body
+		mux.HandleFunc(p, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>test</title>%s</head><body></body></html>", body)
+		})
+	}
+	for p, body := range responses {
+		body := body
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+			fmt.Fprint(w, body)
+		})
+	}
+
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	tests := map[string]struct {
+		link      string
+		maxWidth  int
+		maxHeight int
+		want      *Embed
+		query     string
+	}{
+		"video": {"/video", 640, 0, &Embed{
+			Source:   "/video",
+			Endpoint: "/oembed/video?format=json&maxwidth=640",
+			Response: Response{
+				Type: "video", Version: "1.0", Title: "A video", AuthorName: "Jane", AuthorURL: "https://jane.example/", CacheAge: 3600,
+				ThumbnailURL: "https://player.example/1.jpg", ThumbnailWidth: 320, ThumbnailHeight: 180, Width: 640, Height: 360,
+			},
+			UntrustedHTML: `<iframe src="https://player.example/1"></iframe>`,
+		}, "format=json&maxwidth=640"},
+		"maxwidth ignored": {"/video", 300, 0, &Embed{
+			Source:   "/video",
+			Endpoint: "/oembed/video?format=json&maxwidth=300",
+			Response: Response{
+				Type: "link", Version: "1.0", Title: "A video", AuthorName: "Jane", AuthorURL: "https://jane.example/", CacheAge: 3600,
+			},
+		}, "format=json&maxwidth=300"},
+		"maxheight ignored": {"/video", 0, 200, &Embed{
+			Source:   "/video",
+			Endpoint: "/oembed/video?format=json&maxheight=200&maxwidth=100",
+			Response: Response{
+				Type: "link", Version: "1.0", Title: "A video", AuthorName: "Jane", AuthorURL: "https://jane.example/", CacheAge: 3600,
+				ThumbnailURL: "https://player.example/1.jpg", ThumbnailWidth: 320, ThumbnailHeight: 180,
+			},
+		}, "format=json&maxheight=200&maxwidth=100"},
+		"json preferred": {"/xml", 0, 0, &Embed{
+			Source:   "/xml",
+			Endpoint: "/oembed/link",
+			Response: Response{Type: "link", Version: "1.0", Title: "A link"},
+		}, ""},
+		"xml": {"/only-xml", 800, 600, &Embed{
+			Source:   "/only-xml",
+			Endpoint: "/oembed/photo.xml?maxheight=600&maxwidth=800",
+			Response: Response{Type: "photo", Version: "1.0", Title: "A photo", URL: "https://photos.example/1.jpg", Width: 800, Height: 600},
+		}, "maxheight=600&maxwidth=800"},
+		"invalid": {"/invalid", 0, 0, nil, ""},
+		"missing": {"/missing", 0, 0, nil, ""},
+		"none":    {"/none", 0, 0, nil, ""},
+		"no page": {"/nonexistent", 0, 0, nil, ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			queries = nil
+			got, _, err := Fetch(s.URL+tc.link, tc.maxWidth, tc.maxHeight)
+			if tc.want == nil {
+				if err == nil {
+					t.Fatalf("want error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			tc.want.Source = s.URL + tc.want.Source
+			tc.want.Endpoint = s.URL + tc.want.Endpoint
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+			if len(queries) != 1 || queries[0] != tc.query {
+				t.Fatalf("want provider query %q, got %q", tc.query, queries)
+			}
+		})
+	}
+}
+
+func TestFromFields(t *testing.T) {
+	tests := map[string]struct {
+		fields map[string]string
+		valid  bool
+	}{
+		"photo":           {map[string]string{"version": "1.0", "type": "photo", "url": "https://p.example/1.jpg", "width": "1", "height": "1"}, true},
+		"numeric version": {map[string]string{"version": "1", "type": "link"}, true},
+		"no version":      {map[string]string{"type": "link"}, false},
+		"other version":   {map[string]string{"version": "2.0", "type": "link"}, false},
+		"unknown type":    {map[string]string{"version": "1.0", "type": "audio"}, false},
+		"photo no size":   {map[string]string{"version": "1.0", "type": "photo", "url": "https://p.example/1.jpg"}, false},
+		"photo bad url":   {map[string]string{"version": "1.0", "type": "photo", "url": "data:image/png,", "width": "1", "height": "1"}, false},
+		"video no html":   {map[string]string{"version": "1.0", "type": "video", "width": "1", "height": "1"}, false},
+		"rich":            {map[string]string{"version": "1.0", "type": "rich", "html": "<p>hi</p>", "width": "1", "height": "1"}, true},
+		"rich bad size":   {map[string]string{"version": "1.0", "type": "rich", "html": "<p>hi</p>", "width": "wide", "height": "1"}, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := FromFields(tc.fields)
+			if tc.valid && err != nil {
+				t.Fatalf("want valid, got error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("want error, got %+v", r)
+			}
+			if tc.valid && r.Version != Version {
+				t.Fatalf("want version %q, got %q", Version, r.Version)
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	tests := map[string]struct {
+		page string
+		want string
+	}{
+		"bad href skipped": {`<link rel="alternate" type="application/json+oembed" href="javascript:void(0)">` +
+			`<link rel="alternate" type="application/json+oembed" href="https://provider.example/oembed">`, "https://provider.example/oembed"},
+		"not alternate": {`<link rel="stylesheet" type="application/json+oembed" href="https://provider.example/oembed">`, ""},
+		"none":          {``, ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + tc.page + "</head></html>"))
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if got := Discover(d, nil); got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package oembed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"image"
+	_ "image/gif"  // register GIF for thumbnail sizes
+	_ "image/jpeg" // register JPEG for thumbnail sizes
+	_ "image/png"  // register PNG for thumbnail sizes
+	"net/http"
+	"net/url"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/authorship"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/pageinfo"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// The dimensions of the HTML card of the rich responses; a card narrower
+// than minCardWidth is not worth it, the link response is returned instead
+const (
+	cardWidth    = 500
+	cardHeight   = 150
+	minCardWidth = 200
+)
+
+// Provide returns the oEmbed response for the page at the given URL, built
+// from its page information and the h-card of its author, fitting within
+// maxWidth and maxHeight (if not 0), together with the response header.
+func Provide(link string, maxWidth, maxHeight int) (*Response, *http.Header, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &res.Header, fmt.Errorf("page returned %s", res.Status)
+	}
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, &res.Header, err
+	}
+
+	u = res.Request.URL
+	r := FromDocument(d, u, pageAuthor(d, u), maxWidth, maxHeight)
+	if r.ThumbnailURL != "" {
+		if w, h, ok := imageSize(r.ThumbnailURL); ok {
+			r.ThumbnailWidth, r.ThumbnailHeight = w, h
+		} else {
+			r.ThumbnailURL = ""
+		}
+		r.fit(maxWidth, maxHeight)
+	}
+	return &r, &res.Header, nil
+}
+
+// FromDocument returns the oEmbed response for a document retrieved from the
+// given URL and written by the author (if known), fitting within maxWidth
+// and maxHeight (if not 0): a rich one with an HTML card if the card fits, a
+// link one otherwise. The thumbnail is the image of the page, its dimensions
+// are left for the caller to fill in.
+func FromDocument(d *goquery.Document, u *url.URL, author *hcard.HCard, maxWidth, maxHeight int) Response {
+	pi := pageinfo.FromDocument(d, u)
+	if pi.URL == "" {
+		pi.URL = u.String()
+	}
+
+	r := Response{
+		Type:         "link",
+		Version:      Version,
+		Title:        pi.Title,
+		ProviderName: u.Hostname(),
+		ProviderURL:  (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String(),
+		ThumbnailURL: pi.Image,
+	}
+
+	if author != nil {
+		r.AuthorName, r.AuthorURL = authorNameURL(author)
+	}
+
+	width := cardWidth
+	if maxWidth > 0 && maxWidth < width {
+		width = maxWidth
+	}
+	if width < minCardWidth || !fits(cardHeight, maxHeight) {
+		return r
+	}
+
+	r.Type = "rich"
+	r.Width, r.Height = width, cardHeight
+	r.HTML = card(pi, r, width)
+	return r
+}
+
+// EncodeXML returns the XML representation of the oEmbed response
+func EncodeXML(r *Response) ([]byte, error) {
+	b, err := xml.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// pageAuthor returns the author of the primary h-entry of the document, or
+// the representative h-card of the page; the author page is fetched if
+// needed
+func pageAuthor(d *goquery.Document, u *url.URL) *hcard.HCard {
+	if a, err := authorship.FromDocument(d, u); err == nil {
+		return &a.HCard
+	}
+	if hc, err := hcard.FromDocument(d, u); err == nil {
+		return hc
+	}
+	return nil
+}
+
+// authorNameURL returns the name and URL of the author described by the
+// h-card
+func authorNameURL(hc *hcard.HCard) (name, link string) {
+	name = hc.PName
+	for _, v := range hc.URL {
+		if link = urlnorm.Resolve(nil, v); link != "" {
+			break
+		}
+	}
+	return name, link
+}
+
+// card returns the HTML card of the page, width pixels wide
+func card(pi pageinfo.Info, r Response, width int) string {
+	title := pi.Title
+	if title == "" {
+		title = pi.URL
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<blockquote class="indieweb-embed" style="box-sizing:border-box;width:%dpx;max-width:100%%;height:%dpx;overflow:hidden;margin:0;padding:12px;border:1px solid #ccc;border-radius:8px;font-family:sans-serif">`, width, cardHeight)
+	fmt.Fprintf(&b, `<p style="margin:0 0 8px;font-weight:bold"><a href="%s">%s</a></p>`, html.EscapeString(pi.URL), html.EscapeString(title))
+	if pi.Description != "" {
+		fmt.Fprintf(&b, `<p style="margin:0 0 8px">%s</p>`, html.EscapeString(pi.Description))
+	}
+	switch {
+	case r.AuthorName != "" && r.AuthorURL != "":
+		fmt.Fprintf(&b, `<p style="margin:0">&mdash; <a href="%s">%s</a></p>`, html.EscapeString(r.AuthorURL), html.EscapeString(r.AuthorName))
+	case r.AuthorName != "":
+		fmt.Fprintf(&b, `<p style="margin:0">&mdash; %s</p>`, html.EscapeString(r.AuthorName))
+	}
+	b.WriteString(`</blockquote>`)
+	return b.String()
+}
+
+// fits reports whether the size fits within max, 0 meaning no limit
+func fits(size, max int) bool {
+	return max == 0 || size <= max
+}
+
+// imageSize returns the dimensions of the GIF, JPEG or PNG image at the URL
+func imageSize(link string) (int, int, bool) {
+	res, err := http.Get(link)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, 0, false
+	}
+
+	c, _, err := image.DecodeConfig(res.Body)
+	if err != nil {
+		return 0, 0, false
+	}
+	return c.Width, c.Height, true
+}
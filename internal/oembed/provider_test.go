@@ -0,0 +1,163 @@
+package oembed
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestProvide(t *testing.T) {
+	var thumbnail bytes.Buffer
+	if err := png.Encode(&thumbnail, image.NewRGBA(image.Rect(0, 0, 120, 90))); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Fish &amp; chips</title><meta property="og:image" content="/thumb.png"></head><body>`+
+			`<article class="h-entry"><h1 class="p-name">Fish & chips</h1><p class="p-summary">A <b>tasty</b> meal.</p>`+
+			`<a class="p-author h-card" href="https://jane.example/">Jane</a></article></body></html>`)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Jane's site</title></head><body>`+
+			`<div class="h-card"><a class="u-url u-uid p-name" href="/home">Jane Doe</a></div></body></html>`)
+	})
+	mux.HandleFunc("/thumb.png", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(thumbnail.Bytes())
+	})
+
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	tests := map[string]struct {
+		link      string
+		maxWidth  int
+		maxHeight int
+		want      Response
+	}{
+		"rich": {"/post", 0, 0, Response{
+			Type: "rich", Version: "1.0", Title: "Fish & chips", AuthorName: "Jane", AuthorURL: "https://jane.example/",
+			ThumbnailURL: "/thumb.png", ThumbnailWidth: 120, ThumbnailHeight: 90, Width: 500, Height: 150,
+		}},
+		"narrower": {"/post", 300, 0, Response{
+			Type: "rich", Version: "1.0", Title: "Fish & chips", AuthorName: "Jane", AuthorURL: "https://jane.example/",
+			ThumbnailURL: "/thumb.png", ThumbnailWidth: 120, ThumbnailHeight: 90, Width: 300, Height: 150,
+		}},
+		"too narrow for card": {"/post", 150, 0, Response{
+			Type: "link", Version: "1.0", Title: "Fish & chips", AuthorName: "Jane", AuthorURL: "https://jane.example/",
+			ThumbnailURL: "/thumb.png", ThumbnailWidth: 120, ThumbnailHeight: 90,
+		}},
+		"too low for thumbnail": {"/post", 0, 80, Response{
+			Type: "link", Version: "1.0", Title: "Fish & chips", AuthorName: "Jane", AuthorURL: "https://jane.example/",
+		}},
+		"representative h-card": {"/home", 0, 0, Response{
+			Type: "rich", Version: "1.0", Title: "Jane's site", AuthorName: "Jane Doe", AuthorURL: "/home", Width: 500, Height: 150,
+		}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _, err := Provide(s.URL+tc.link, tc.maxWidth, tc.maxHeight)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if tc.want.ThumbnailURL != "" {
+				tc.want.ThumbnailURL = s.URL + tc.want.ThumbnailURL
+			}
+			if strings.HasPrefix(tc.want.AuthorURL, "/") {
+				tc.want.AuthorURL = s.URL + tc.want.AuthorURL
+			}
+			tc.want.ProviderName = "127.0.0.1"
+			tc.want.ProviderURL = s.URL + "/"
+
+			html := got.HTML
+			got.HTML = ""
+			if *got != tc.want {
+				t.Fatalf("want %+v, got %+v", tc.want, *got)
+			}
+			if (tc.want.Type == "rich") != (html != "") {
+				t.Fatalf("want HTML only for rich responses, got %q", html)
+			}
+		})
+	}
+}
+
+func TestFromDocument(t *testing.T) {
+	d, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><title>Fish</title>` +
+		`<meta property="og:image" content="https://img.invalid/fish.png"></head></html>`))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	u, _ := url.Parse("https://jane.example/fish")
+	author := &hcard.HCard{PName: "Jane", URL: []string{"https://jane.example/"}}
+
+	got := FromDocument(d, u, author, 0, 0)
+	got.HTML = ""
+	want := Response{
+		Type: "rich", Version: "1.0", Title: "Fish", AuthorName: "Jane", AuthorURL: "https://jane.example/",
+		ProviderName: "jane.example", ProviderURL: "https://jane.example/", ThumbnailURL: "https://img.invalid/fish.png",
+		Width: 500, Height: 150,
+	}
+	if got != want {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestCard(t *testing.T) {
+	got, _, err := Provide("", 0, 0)
+	if err == nil {
+		t.Fatalf("want error for empty link, got %+v", got)
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><head><title>"><script>alert(1)</script></title>`+
+			`<meta name="description" content="Less <than> more"></head></html>`)
+	}))
+	defer s.Close()
+
+	r, _, err := Provide(s.URL, 0, 0)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	for _, want := range []string{
+		`<a href="` + s.URL + `">&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;</a>`,
+		`<p style="margin:0 0 8px">Less &lt;than&gt; more</p>`,
+		`width:500px;`,
+	} {
+		if !strings.Contains(r.HTML, want) {
+			t.Fatalf("want %q to contain %q", r.HTML, want)
+		}
+	}
+}
+
+func TestEncodeXML(t *testing.T) {
+	b, err := EncodeXML(&Response{Type: "rich", Version: "1.0", Title: "Fish & chips", HTML: "<p>hi</p>", Width: 500, Height: 150})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<oembed><type>rich</type><version>1.0</version><title>Fish &amp; chips</title>` +
+		`<html>&lt;p&gt;hi&lt;/p&gt;</html><width>500</width><height>150</height></oembed>`
+	if string(b) != want {
+		t.Fatalf("want %s, got %s", want, b)
+	}
+
+	fields, err := parseXML(b)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if r, err := FromFields(fields); err != nil || r.HTML != "<p>hi</p>" {
+		t.Fatalf("want the response to round-trip, got %+v, %v", r, err)
+	}
+}
@@ -20,15 +20,84 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
 	"github.com/PuerkitoBio/goquery"
 )
 
-// OpenGraph represents OpenGraph information
+// OpenGraph represents OpenGraph information, see https://ogp.me/
 type OpenGraph struct {
-	Title       string `json:"title"`
-	Image       string `json:"image,omitempty"`
-	Description string `json:"description,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	Image           string   `json:"image,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	SiteName        string   `json:"siteName,omitempty"`
+	Determiner      string   `json:"determiner,omitempty"`
+	Locale          string   `json:"locale,omitempty"`
+	LocaleAlternate []string `json:"localeAlternate,omitempty"`
+	Images          []Media  `json:"images,omitempty"`
+	Videos          []Media  `json:"videos,omitempty"`
+	Audio           []Media  `json:"audio,omitempty"`
+	Article         *Article `json:"article,omitempty"`
+	Profile         *Profile `json:"profile,omitempty"`
+	Book            *Book    `json:"book,omitempty"`
+	Music           *Music   `json:"music,omitempty"`
+}
+
+// Media represents a structured og:image, og:video or og:audio property
+type Media struct {
+	URL       string `json:"url,omitempty"`
+	SecureURL string `json:"secureUrl,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	Alt       string `json:"alt,omitempty"`
+}
+
+// Article represents the article:* properties
+type Article struct {
+	PublishedTime  string   `json:"publishedTime,omitempty"`
+	ModifiedTime   string   `json:"modifiedTime,omitempty"`
+	ExpirationTime string   `json:"expirationTime,omitempty"`
+	Author         []string `json:"author,omitempty"`
+	Section        string   `json:"section,omitempty"`
+	Tag            []string `json:"tag,omitempty"`
+}
+
+// Profile represents the profile:* properties
+type Profile struct {
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Gender    string `json:"gender,omitempty"`
+}
+
+// Book represents the book:* properties
+type Book struct {
+	Author      []string `json:"author,omitempty"`
+	ISBN        string   `json:"isbn,omitempty"`
+	ReleaseDate string   `json:"releaseDate,omitempty"`
+	Tag         []string `json:"tag,omitempty"`
+}
+
+// Music represents the music:* properties
+type Music struct {
+	Duration    int      `json:"duration,omitempty"`
+	Album       []Track  `json:"album,omitempty"`
+	Song        []Track  `json:"song,omitempty"`
+	Musician    []string `json:"musician,omitempty"`
+	Creator     []string `json:"creator,omitempty"`
+	ReleaseDate string   `json:"releaseDate,omitempty"`
+}
+
+// Track represents a structured music:album or music:song property
+type Track struct {
+	URL   string `json:"url,omitempty"`
+	Disc  int    `json:"disc,omitempty"`
+	Track int    `json:"track,omitempty"`
 }
 
 // Fetch fetches the page at URI and returns OpenGraph info
@@ -53,7 +122,7 @@ func Fetch(uri string) (*OpenGraph, *http.Header, error) {
 		return nil, nil, err
 	}
 
-	og, err := FromDocument(d)
+	og, err := FromDocument(d, res.Request.URL)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -61,20 +130,294 @@ func Fetch(uri string) (*OpenGraph, *http.Header, error) {
 	return &og, &res.Header, nil
 }
 
-// FromDocument returns OpenGraph properties from a document
-func FromDocument(d *goquery.Document) (OpenGraph, error) {
-	title, ok := d.Find("meta[property=\"og:title\"]").Attr("content")
-	if !ok {
-		return OpenGraph{}, fmt.Errorf("no opengraph title property found")
+// FromDocument returns OpenGraph properties from a document retrieved from
+// the given URL. Structured properties (like og:image:width) refer to the
+// last root property (og:image) before them. URLs are resolved against the
+// base of the document, those that aren't valid absolute http(s) URLs are
+// dropped.
+func FromDocument(d *goquery.Document, u *url.URL) (OpenGraph, error) {
+	var og OpenGraph
+	found := false
+
+	d.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		property, ok := s.Attr("property")
+		if !ok {
+			property, ok = s.Attr("name")
+		}
+		content, hasContent := s.Attr("content")
+		if !ok || !hasContent {
+			return
+		}
+
+		property = strings.ToLower(strings.TrimSpace(property))
+		content = strings.TrimSpace(content)
+		if og.set(property, content) {
+			found = true
+		}
+	})
+
+	if !found {
+		return OpenGraph{}, fmt.Errorf("no opengraph properties found")
 	}
-	og := OpenGraph{Title: title}
 
-	if image, ok := d.Find("meta[property=\"og:image\"]").Attr("content"); ok {
-		og.Image = image
+	og.resolve(urlnorm.Base(d, u))
+	return og, nil
+}
+
+// resolve resolves the URLs of OpenGraph information against the base URL
+func (og *OpenGraph) resolve(base *url.URL) {
+	og.URL = urlnorm.Resolve(base, og.URL)
+	og.Images = resolveMedia(og.Images, base)
+	og.Videos = resolveMedia(og.Videos, base)
+	og.Audio = resolveMedia(og.Audio, base)
+
+	og.Image = ""
+	if len(og.Images) > 0 {
+		og.Image = og.Images[0].URL
+		if og.Image == "" {
+			og.Image = og.Images[0].SecureURL
+		}
 	}
 
-	if desc, ok := d.Find("meta[property=\"og:description\"]").Attr("content"); ok {
-		og.Description = desc
+	if og.Music != nil {
+		og.Music.Album = resolveTracks(og.Music.Album, base)
+		og.Music.Song = resolveTracks(og.Music.Song, base)
 	}
-	return og, nil
+}
+
+// resolveMedia resolves the URLs of the media, dropping the ones left
+// without any
+func resolveMedia(media []Media, base *url.URL) (resolved []Media) {
+	for _, m := range media {
+		m.URL = urlnorm.Resolve(base, m.URL)
+		m.SecureURL = urlnorm.Resolve(base, m.SecureURL)
+		if m.URL != "" || m.SecureURL != "" {
+			resolved = append(resolved, m)
+		}
+	}
+	return
+}
+
+// resolveTracks resolves the URLs of the tracks, dropping the ones left
+// without any
+func resolveTracks(tracks []Track, base *url.URL) (resolved []Track) {
+	for _, t := range tracks {
+		t.URL = urlnorm.Resolve(base, t.URL)
+		if t.URL != "" {
+			resolved = append(resolved, t)
+		}
+	}
+	return
+}
+
+// set sets the property of OpenGraph information and tells whether it is
+// a known one
+func (og *OpenGraph) set(property, content string) bool {
+	ns, name, _ := strings.Cut(property, ":")
+	switch ns {
+	case "og":
+		return og.setOG(name, content)
+	case "article":
+		a := og.Article
+		if a == nil {
+			a = &Article{}
+		}
+		if !a.set(name, content) {
+			return false
+		}
+		og.Article = a
+	case "profile":
+		p := og.Profile
+		if p == nil {
+			p = &Profile{}
+		}
+		if !p.set(name, content) {
+			return false
+		}
+		og.Profile = p
+	case "book":
+		b := og.Book
+		if b == nil {
+			b = &Book{}
+		}
+		if !b.set(name, content) {
+			return false
+		}
+		og.Book = b
+	case "music":
+		m := og.Music
+		if m == nil {
+			m = &Music{}
+		}
+		if !m.set(name, content) {
+			return false
+		}
+		og.Music = m
+	default:
+		return false
+	}
+	return true
+}
+
+func (og *OpenGraph) setOG(name, content string) bool {
+	switch name {
+	case "title":
+		og.Title = content
+	case "type":
+		og.Type = content
+	case "url":
+		og.URL = content
+	case "description":
+		og.Description = content
+	case "site_name":
+		og.SiteName = content
+	case "determiner":
+		og.Determiner = content
+	case "locale":
+		og.Locale = content
+	case "locale:alternate":
+		og.LocaleAlternate = append(og.LocaleAlternate, content)
+	default:
+		root, sub, _ := strings.Cut(name, ":")
+		switch root {
+		case "image":
+			og.Images = setMedia(og.Images, sub, content)
+		case "video":
+			og.Videos = setMedia(og.Videos, sub, content)
+		case "audio":
+			og.Audio = setMedia(og.Audio, sub, content)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// setMedia sets the structured property of the last media, starting a new
+// one for the root property
+func setMedia(media []Media, property, content string) []Media {
+	if len(media) == 0 || (property == "" || property == "url") && media[len(media)-1].URL != "" {
+		media = append(media, Media{})
+	}
+
+	m := &media[len(media)-1]
+	switch property {
+	case "", "url":
+		m.URL = content
+	case "secure_url":
+		m.SecureURL = content
+	case "type":
+		m.Type = content
+	case "width":
+		m.Width = number(content)
+	case "height":
+		m.Height = number(content)
+	case "alt":
+		m.Alt = content
+	}
+	return media
+}
+
+func (a *Article) set(name, content string) bool {
+	switch name {
+	case "published_time":
+		a.PublishedTime = content
+	case "modified_time":
+		a.ModifiedTime = content
+	case "expiration_time":
+		a.ExpirationTime = content
+	case "author":
+		a.Author = append(a.Author, content)
+	case "section":
+		a.Section = content
+	case "tag":
+		a.Tag = append(a.Tag, content)
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *Profile) set(name, content string) bool {
+	switch name {
+	case "first_name":
+		p.FirstName = content
+	case "last_name":
+		p.LastName = content
+	case "username":
+		p.Username = content
+	case "gender":
+		p.Gender = content
+	default:
+		return false
+	}
+	return true
+}
+
+func (b *Book) set(name, content string) bool {
+	switch name {
+	case "author":
+		b.Author = append(b.Author, content)
+	case "isbn":
+		b.ISBN = content
+	case "release_date":
+		b.ReleaseDate = content
+	case "tag":
+		b.Tag = append(b.Tag, content)
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *Music) set(name, content string) bool {
+	switch name {
+	case "duration":
+		m.Duration = number(content)
+	case "musician":
+		m.Musician = append(m.Musician, content)
+	case "creator":
+		m.Creator = append(m.Creator, content)
+	case "release_date":
+		m.ReleaseDate = content
+	default:
+		root, sub, _ := strings.Cut(name, ":")
+		switch root {
+		case "album":
+			m.Album = setTrack(m.Album, sub, content)
+		case "song":
+			m.Song = setTrack(m.Song, sub, content)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// setTrack sets the structured property of the last track, starting a new
+// one for the root property
+func setTrack(tracks []Track, property, content string) []Track {
+	if len(tracks) == 0 || property == "" || property == "url" {
+		tracks = append(tracks, Track{})
+	}
+
+	t := &tracks[len(tracks)-1]
+	switch property {
+	case "", "url":
+		t.URL = content
+	case "disc":
+		t.Disc = number(content)
+	case "track":
+		t.Track = number(content)
+	}
+	return tracks
+}
+
+// number returns the integer value of the content, or 0 if it is not one
+func number(content string) int {
+	n, err := strconv.Atoi(content)
+	if err != nil {
+		return 0
+	}
+	return n
 }
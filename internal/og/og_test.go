@@ -0,0 +1,104 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package og
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestFetch(t *testing.T) {
+	tests := map[string]struct {
+		link string
+		want OpenGraph
+	}{
+		"article without title": {"/article.html", OpenGraph{
+			Type:            "article",
+			URL:             "https://jane.example/2026/walking-the-dog/",
+			Image:           "https://jane.example/dog.jpg",
+			SiteName:        "Jane's blog",
+			Locale:          "en_GB",
+			LocaleAlternate: []string{"fr_FR", "es_ES"},
+			Images: []Media{
+				{URL: "https://jane.example/dog.jpg", SecureURL: "https://secure.jane.example/dog.jpg", Type: "image/jpeg", Width: 1200, Height: 630, Alt: "A dog on a lead"},
+				{URL: "https://jane.example/lake.jpg"},
+			},
+			Videos: []Media{{URL: "https://jane.example/walk.mp4", Type: "video/mp4"}},
+			Audio:  []Media{{URL: "https://jane.example/walk.mp3"}},
+			Article: &Article{
+				PublishedTime: "2026-04-01T10:00:00+02:00",
+				Author:        []string{"https://jane.example/", "https://john.example/"},
+				Section:       "Life",
+				Tag:           []string{"dogs", "walks"},
+			},
+		}},
+		"album": {"/album.html", OpenGraph{
+			Title: "Greatest Hits",
+			Type:  "music.album",
+			Music: &Music{
+				Song:        []Track{{URL: "https://music.example/song/1", Disc: 1, Track: 1}, {URL: "https://music.example/song/2", Track: 2}},
+				Musician:    []string{"https://music.example/band"},
+				ReleaseDate: "2026-01-01",
+			},
+			Profile: &Profile{Username: "band"},
+			Book:    &Book{ISBN: "978-3-16-148410-0"},
+		}},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	tests["relative"] = struct {
+		link string
+		want OpenGraph
+	}{"/relative.html", OpenGraph{
+		Title:  "Relative",
+		URL:    "http://example.com/relative",
+		Image:  s.URL + "/assets/card.png",
+		Images: []Media{{URL: s.URL + "/assets/card.png"}},
+		Videos: []Media{{SecureURL: "https://video.example/clip.mp4"}},
+	}}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _, err := Fetch(s.URL + tc.link)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if !reflect.DeepEqual(*got, tc.want) {
+				t.Fatalf("want %+v, got %+v", tc.want, *got)
+			}
+		})
+	}
+}
+
+func TestNoOpenGraph(t *testing.T) {
+	d, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><meta name="description" content="Nothing here"></head></html>`))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if _, err := FromDocument(d, nil); err == nil {
+		t.Fatalf("want error for a page without OpenGraph properties")
+	}
+}
@@ -21,7 +21,11 @@ import (
 	"net/url"
 	"strings"
 
+	"evgenykuznetsov.org/go/indieweb-glue/internal/jsonld"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/manifest"
 	"evgenykuznetsov.org/go/indieweb-glue/internal/og"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/twittercard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
 	"github.com/PuerkitoBio/goquery"
 	"willnorris.com/go/microformats"
 )
@@ -29,8 +33,13 @@ import (
 // Info represents information about page
 type Info struct {
 	Title       string `json:"title,omitempty"`
+	URL         string `json:"url,omitempty"`
 	Image       string `json:"image,omitempty"`
 	Description string `json:"description,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Published   string `json:"published,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+	ThemeColor  string `json:"themeColor,omitempty"`
 }
 
 // Fetch fetches the page at URI and returns Info
@@ -55,21 +64,35 @@ func Fetch(uri string) (*Info, *http.Header, error) {
 		return nil, nil, err
 	}
 
-	pi := FromDocument(d)
-	if pi.Image == "" {
-		pi.Image = mfImage(d, u)
+	pi := FromDocument(d, res.Request.URL)
+	if pi.SiteName == "" || pi.ThemeColor == "" {
+		if link := manifest.URL(d, res.Request.URL); link != "" {
+			if m, _, err := manifest.Load(link); err == nil {
+				pi.addManifest(m)
+			}
+		}
 	}
-
 	return &pi, &res.Header, nil
 }
 
-// FromDocument returns Info properties from a document
-func FromDocument(d *goquery.Document) Info {
-	o, _ := og.FromDocument(d)
+// FromDocument returns Info properties from a document retrieved from the
+// given URL. URLs are resolved against the base of the document, those that
+// aren't valid absolute http(s) URLs are dropped.
+func FromDocument(d *goquery.Document, u *url.URL) Info {
+	base := urlnorm.Base(d, u)
+	o, _ := og.FromDocument(d, u)
+	tc, _ := twittercard.FromDocument(d, u)
+	content := mfContent(d)
+	ld := jsonld.FromDocument(d, u).Article()
+	if ld == nil {
+		ld = &jsonld.Article{}
+	}
 
 	getTitle := []func(*goquery.Document) string{
-		mfTitle,
+		func(*goquery.Document) string { return mfTitle(content) },
 		func(*goquery.Document) string { return o.Title },
+		func(*goquery.Document) string { return tc.Title },
+		func(*goquery.Document) string { return ld.Headline },
 		func(d *goquery.Document) string { return d.Find("title").Text() },
 	}
 
@@ -82,8 +105,10 @@ func FromDocument(d *goquery.Document) Info {
 	}
 
 	getDescription := []func(*goquery.Document) string{
-		mfDesc,
+		func(*goquery.Document) string { return mfDesc(content) },
 		func(*goquery.Document) string { return o.Description },
+		func(*goquery.Document) string { return tc.Description },
+		func(*goquery.Document) string { return ld.Description },
 		wikiFirstPara,
 		metaDesc,
 	}
@@ -96,13 +121,124 @@ func FromDocument(d *goquery.Document) Info {
 		}
 	}
 
-	return Info{
+	getAuthor := []func(*goquery.Document) string{
+		func(*goquery.Document) string { return mfAuthor(content) },
+		func(*goquery.Document) string {
+			if ld.Author == nil {
+				return ""
+			}
+			return ld.Author.PName
+		},
+	}
+
+	var author string
+	for _, get := range getAuthor {
+		author = get(d)
+		if len(author) != 0 {
+			break
+		}
+	}
+
+	getPublished := []func(*goquery.Document) string{
+		func(*goquery.Document) string { return mfPublished(content) },
+		func(*goquery.Document) string {
+			if o.Article == nil {
+				return ""
+			}
+			return o.Article.PublishedTime
+		},
+		func(*goquery.Document) string { return ld.Published },
+	}
+
+	var published string
+	for _, get := range getPublished {
+		published = get(d)
+		if len(published) != 0 {
+			break
+		}
+	}
+
+	pi := Info{
 		Title:       title,
+		URL:         canonical(d, base),
 		Description: desc,
 		Image:       o.Image,
+		Author:      author,
+		Published:   published,
+		SiteName:    o.SiteName,
+		ThemeColor:  themeColor(d),
+	}
+	if pi.URL == "" {
+		pi.URL = o.URL
+	}
+	if pi.SiteName == "" {
+		pi.SiteName = applicationName(d)
+	}
+	if pi.Image == "" {
+		pi.Image = tc.Image
+	}
+	if pi.Image == "" && len(ld.Image) > 0 {
+		pi.Image = ld.Image[0]
+	}
+	if pi.Image == "" {
+		pi.Image = mfImage(d, base)
+	}
+	return pi
+}
+
+// addManifest fills in the site name and theme color missing from the page
+// with the ones of the web app manifest
+func (pi *Info) addManifest(m *manifest.Manifest) {
+	if pi.SiteName == "" {
+		pi.SiteName = m.Name
+	}
+	if pi.SiteName == "" {
+		pi.SiteName = m.ShortName
+	}
+	if pi.ThemeColor == "" {
+		pi.ThemeColor = m.ThemeColor
 	}
 }
 
+// themeColor returns the theme color of the page, preferring the one not
+// limited to a media query
+func themeColor(d *goquery.Document) string {
+	var color string
+	d.Find("meta[name][content]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		name, _ := s.Attr("name")
+		if !strings.EqualFold(strings.TrimSpace(name), "theme-color") {
+			return true
+		}
+		content, _ := s.Attr("content")
+		if content = strings.TrimSpace(content); content == "" {
+			return true
+		}
+		_, media := s.Attr("media")
+		if color == "" || !media {
+			color = content
+		}
+		return media
+	})
+	return color
+}
+
+// canonical returns the canonical URL of the page
+func canonical(d *goquery.Document, base *url.URL) string {
+	var link string
+	d.Find("link[rel][href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		rel, _ := s.Attr("rel")
+		for _, r := range strings.Fields(strings.ToLower(rel)) {
+			if r == "canonical" {
+				href, _ := s.Attr("href")
+				link = urlnorm.Resolve(base, href)
+				return false
+			}
+		}
+		return true
+	})
+	return link
+}
+
 // wikiFirstPara returns the first paragraph of text if d is a wiki page
 func wikiFirstPara(d *goquery.Document) string {
 	gen, ok := d.Find("meta[name=\"generator\"]").Attr("content")
@@ -124,39 +260,61 @@ func metaDesc(d *goquery.Document) string {
 	return desc
 }
 
+// applicationName returns the "application-name" meta-tag content
+func applicationName(d *goquery.Document) string {
+	name, _ := d.Find("meta[name=\"application-name\"]").Attr("content")
+	return strings.TrimSpace(name)
+}
+
 // mfImage returns the image representing a page that has microformats on it.
-func mfImage(d *goquery.Document, u *url.URL) string {
+func mfImage(d *goquery.Document, base *url.URL) string {
 	i, ok := d.Find("img.u-featured").Attr("src")
 	if !ok {
 		return ""
 	}
-	uri, err := u.Parse(i)
-	if err != nil {
-		return ""
+	return urlnorm.Resolve(base, i)
+}
+
+// mfContent returns the microformat with id="content" of the document, the
+// one the page information is taken from, or nil if there is none
+func mfContent(d *goquery.Document) *microformats.Microformat {
+	data := microformats.ParseNode(d.Get(0), nil)
+	for _, item := range data.Items {
+		if item.ID == "content" {
+			return item
+		}
 	}
-	return uri.String()
+	return nil
 }
 
 // mfDesc returns the description of a page that has microformats on it.
-func mfDesc(d *goquery.Document) string {
-	return mfProperty(d, "summary")
+func mfDesc(m *microformats.Microformat) string {
+	return mfProperty(m, "summary")
 }
 
 // mfTitle returns the title of a page that has microformats on it.
-func mfTitle(d *goquery.Document) string {
-	return mfProperty(d, "name")
+func mfTitle(m *microformats.Microformat) string {
+	return mfProperty(m, "name")
+}
+
+// mfAuthor returns the name of the author of a page that has microformats on
+// it.
+func mfAuthor(m *microformats.Microformat) string {
+	return mfProperty(m, "author")
+}
+
+// mfPublished returns the publication date of a page that has microformats on
+// it.
+func mfPublished(m *microformats.Microformat) string {
+	return mfProperty(m, "published")
 }
 
 // mfProperty returns the text property of microformatted content
-func mfProperty(d *goquery.Document, p string) string {
-	data := microformats.ParseNode(d.Get(0), nil)
-	for _, item := range data.Items {
-		if item.ID == "content" {
-			n := item.Properties[p]
-			return getString(n)
-		}
+func mfProperty(m *microformats.Microformat, p string) string {
+	if m == nil {
+		return ""
 	}
-	return ""
+	return getString(m.Properties[p])
 }
 
 // getString returns a string value nested in interface{}
@@ -169,6 +327,11 @@ func getString(n interface{}) string {
 			return ""
 		}
 		return getString(v[0])
+	case *microformats.Microformat:
+		if name := getString(v.Properties["name"]); name != "" {
+			return name
+		}
+		return v.Value
 	default:
 		return ""
 	}
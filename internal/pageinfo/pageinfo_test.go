@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -122,6 +123,87 @@ func TestTitle(t *testing.T) {
 	}
 }
 
+func TestURLs(t *testing.T) {
+	tests := map[string]struct {
+		filename string
+		url      string
+		image    string
+	}{
+		"base":          {"relative_og.html", "https://cdn.example/canonical", "https://cdn.example/assets/img/card.png"},
+		"no base":       {"sedgewick.html", "https://ru.wikipedia.org/wiki/%D0%A1%D0%B5%D0%B4%D0%B6%D0%B2%D0%B8%D0%BA,_%D0%A0%D0%BE%D0%B1%D0%B5%D1%80%D1%82", "https://upload.wikimedia.org/wikipedia/commons/d/d1/Robertsedgewick.jpg"},
+		"not http(s)":   {"bad_urls.html", "", ""},
+		"relative base": {"relative_base.html", "", ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pi := piFromFile(t, tc.filename)
+			if pi.URL != tc.url {
+				t.Fatalf("want URL %q, got %q", tc.url, pi.URL)
+			}
+			if pi.Image != tc.image {
+				t.Fatalf("want image %q, got %q", tc.image, pi.Image)
+			}
+		})
+	}
+}
+
+func TestTwitterCardFallback(t *testing.T) {
+	pi := piFromFile(t, "twitter.html")
+
+	want := Info{Title: "Card title", Description: "Card description", Image: "https://example.com/card.png"}
+	if pi != want {
+		t.Fatalf("want %+v, got %+v", want, pi)
+	}
+}
+
+func TestJSONLDFallback(t *testing.T) {
+	pi := piFromFile(t, "jsonld.html")
+
+	want := Info{
+		Title:       "Fish and chips",
+		Description: "A tasty meal.",
+		Image:       "https://example.com/img/fish.jpg",
+		Author:      "Jane Doe",
+		Published:   "2026-09-01T12:00:00+02:00",
+	}
+	if pi != want {
+		t.Fatalf("want %+v, got %+v", want, pi)
+	}
+}
+
+func TestSite(t *testing.T) {
+	tests := map[string]struct {
+		link       string
+		siteName   string
+		themeColor string
+	}{
+		"page":     {"/site.html", "Open Graph site", "#ff6600"},
+		"manifest": {"/manifest.html", "Manifest site", "#336699"},
+		"app name": {"/app_name.html", "Application site", "#336699"},
+		"none":     {"/twitter.html", "", ""},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pi, _, err := Fetch(fmt.Sprintf("%s%s", s.URL, tc.link))
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if pi.SiteName != tc.siteName {
+				t.Fatalf("want site name %q, got %q", tc.siteName, pi.SiteName)
+			}
+			if pi.ThemeColor != tc.themeColor {
+				t.Fatalf("want theme color %q, got %q", tc.themeColor, pi.ThemeColor)
+			}
+		})
+	}
+}
+
 func piFromFile(t *testing.T, filename string) Info {
 	t.Helper()
 	f, err := os.Open(filepath.Join("testdata", filename))
@@ -133,5 +215,9 @@ func piFromFile(t *testing.T, filename string) Info {
 	if err != nil {
 		t.Fatal(err)
 	}
-	return FromDocument(d)
+	u, err := url.Parse("https://example.com/" + filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return FromDocument(d, u)
 }
@@ -0,0 +1,149 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package posttype implements Post Type Discovery, see
+// https://www.w3.org/TR/post-type-discovery/, and post name discovery.
+package posttype
+
+import (
+	"net/url"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	mf "willnorris.com/go/microformats"
+)
+
+// nameLength is the maximum length of a name made from the post content
+const nameLength = 60
+
+// Post represents the discovered type and name of a post
+type Post struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// Discover returns the type and name of the post
+func Discover(m *mf.Microformat) Post {
+	return Post{Type: Type(m), Name: Name(m)}
+}
+
+// Type returns the type of the post determined by the Post Type Algorithm
+func Type(m *mf.Microformat) string {
+	if m == nil {
+		return ""
+	}
+
+	if mf2.HasType(m, "h-event") {
+		return "event"
+	}
+
+	switch strings.ToLower(mf2.Property(m, "rsvp")) {
+	case "yes", "no", "maybe", "interested":
+		return "rsvp"
+	}
+
+	for _, t := range []struct{ property, kind string }{
+		{"repost-of", "repost"},
+		{"like-of", "like"},
+		{"in-reply-to", "reply"},
+		{"bookmark-of", "bookmark"},
+		{"video", "video"},
+		{"photo", "photo"},
+	} {
+		if hasURL(m, t.property) {
+			return t.kind
+		}
+	}
+
+	content := content(m)
+	if content == "" {
+		return "note"
+	}
+
+	name := collapse(mf2.Property(m, "name"))
+	if name == "" {
+		return "note"
+	}
+
+	if !strings.HasPrefix(content, name) {
+		return "article"
+	}
+	return "note"
+}
+
+// Name returns the name of the post: its name if it is an article,
+// otherwise its content (or summary) truncated at a word boundary.
+func Name(m *mf.Microformat) string {
+	if m == nil {
+		return ""
+	}
+
+	name := collapse(mf2.Property(m, "name"))
+	content := content(m)
+	if content == "" || (name != "" && !strings.HasPrefix(content, name)) {
+		return name
+	}
+	return truncate(content, nameLength)
+}
+
+// content returns the text of the post content, or of its summary if there
+// is no content, with whitespace collapsed
+func content(m *mf.Microformat) string {
+	if c := collapse(mf2.Property(m, "content")); c != "" {
+		return c
+	}
+	return collapse(mf2.Property(m, "summary"))
+}
+
+// hasURL reports whether any value of the property is a valid URL
+func hasURL(m *mf.Microformat, property string) bool {
+	for _, v := range m.Properties[property] {
+		link := mf2.Value(v)
+		if e, ok := v.(*mf.Microformat); ok && !validURL(link) {
+			link = mf2.Property(e, "url")
+		}
+		if validURL(link) {
+			return true
+		}
+	}
+	return false
+}
+
+func validURL(s string) bool {
+	if s == "" {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to at most n runes, cutting at a word boundary and
+// adding an ellipsis if s was shortened
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	cut := string(r[:n])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:!?-") + "…"
+}
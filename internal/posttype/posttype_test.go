@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package posttype
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	mf "willnorris.com/go/microformats"
+)
+
+func TestDiscover(t *testing.T) {
+	tests := map[string]struct {
+		html string
+		want Post
+	}{
+		"event": {
+			`<div class="h-event"><span class="p-name">Party</span></div>`,
+			Post{"event", "Party"},
+		},
+		"rsvp": {
+			`<div class="h-entry"><data class="p-rsvp" value="yes">I'll be there</data><a class="u-in-reply-to" href="https://other.example/party">party</a></div>`,
+			Post{"rsvp", ""},
+		},
+		"repost": {
+			`<div class="h-entry"><a class="u-repost-of" href="https://other.example/post">reposted</a></div>`,
+			Post{"repost", "reposted"},
+		},
+		"like": {
+			`<div class="h-entry"><div class="u-like-of h-cite"><a class="u-url" href="https://other.example/post">a post</a></div></div>`,
+			Post{"like", ""},
+		},
+		"reply": {
+			`<div class="h-entry"><a class="u-in-reply-to" href="https://other.example/post">re</a><p class="e-content">Totally agree!</p></div>`,
+			Post{"reply", "Totally agree!"},
+		},
+		"bookmark": {
+			`<div class="h-entry"><a class="u-bookmark-of" href="https://other.example/post">read later</a></div>`,
+			Post{"bookmark", "read later"},
+		},
+		"video": {
+			`<div class="h-entry"><video class="u-video" src="https://example.com/v.mp4"></video><p class="e-content">Look at this</p></div>`,
+			Post{"video", "Look at this"},
+		},
+		"photo": {
+			`<div class="h-entry"><img class="u-photo" src="https://example.com/p.jpg" alt=""><p class="p-name e-content">Sunset</p></div>`,
+			Post{"photo", "Sunset"},
+		},
+		"article": {
+			`<div class="h-entry"><h1 class="p-name">On Things</h1><div class="e-content">Things are   interesting.</div></div>`,
+			Post{"article", "On Things"},
+		},
+		"note": {
+			`<div class="h-entry"><p class="p-name e-content">Just a   quick note.</p></div>`,
+			Post{"note", "Just a quick note."},
+		},
+		"note without name": {
+			`<div class="h-entry"><div class="e-content"><p>Hello</p></div><a class="u-url" href="/1">#</a></div>`,
+			Post{"note", "Hello"},
+		},
+		"long note": {
+			`<div class="h-entry"><p class="e-content">This note is quite a bit longer than anyone would like a title to be, really.</p></div>`,
+			Post{"note", "This note is quite a bit longer than anyone would like a…"},
+		},
+	}
+
+	base, _ := url.Parse("https://example.com/")
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := mf.Parse(strings.NewReader(tc.html), base)
+			got := Discover(d.Items[0])
+			if got != tc.want {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+
+	if got := Discover(nil); got != (Post{}) {
+		t.Fatalf("want empty post for nil, got %+v", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package relme provides RelMeAuth-style verification of rel=me links
+// between two pages, see https://microformats.org/wiki/RelMeAuth
+package relme
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/discover"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	mf "willnorris.com/go/microformats"
+)
+
+// Kinds of hops in the verification path
+const (
+	KindRedirect = "redirect"
+	KindRelMe    = "rel-me"
+)
+
+// Result represents the result of a rel=me verification
+type Result struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Forward  bool   `json:"forward"`
+	Backward bool   `json:"backward"`
+	Verified bool   `json:"verified"`
+	Path     []Hop  `json:"path,omitempty"`
+}
+
+// Hop represents a single step in the verification path
+type Hop struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// page represents a fetched page
+type page struct {
+	// identities are the URLs the page is known by: the requested URL and
+	// the targets of permanent redirects from it
+	identities []string
+	redirects  []Hop
+	final      string
+	links      []string
+	header     http.Header
+}
+
+// Fetch fetches both pages and checks whether they link to each other with
+// rel=me links, returning the result together with the response headers of
+// both pages.
+func Fetch(from, to string) (*Result, *http.Header, *http.Header, error) {
+	a, err := fetchPage(from)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	b, err := fetchPage(to)
+	if err != nil {
+		return nil, &a.header, nil, err
+	}
+
+	r := Result{From: a.identities[0], To: b.identities[0]}
+
+	forward, ok := findLink(a.links, b.identities)
+	if ok {
+		r.Forward = true
+		r.Path = append(r.Path, a.redirects...)
+		r.Path = append(r.Path, Hop{From: a.final, To: forward, Kind: KindRelMe})
+	}
+
+	backward, ok := findLink(b.links, a.identities)
+	if ok {
+		r.Backward = true
+		r.Path = append(r.Path, b.redirects...)
+		r.Path = append(r.Path, Hop{From: b.final, To: backward, Kind: KindRelMe})
+	}
+
+	r.Verified = r.Forward && r.Backward
+
+	return &r, &a.header, &b.header, nil
+}
+
+// fetchPage fetches the page at URI following the redirects and collects
+// its rel=me links
+func fetchPage(uri string) (*page, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	p := page{identities: []string{u.String()}}
+	permanent := true
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			prev := via[len(via)-1].URL
+			if prev.Scheme == "https" && req.URL.Scheme != "https" {
+				return fmt.Errorf("insecure redirect from %s to %s", prev, req.URL)
+			}
+
+			p.redirects = append(p.redirects, Hop{From: prev.String(), To: req.URL.String(), Kind: KindRedirect})
+
+			code := req.Response.StatusCode
+			permanent = permanent && (code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect)
+			if permanent {
+				p.identities = append(p.identities, req.URL.String())
+			}
+			return nil
+		},
+	}
+
+	res, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	p.final = res.Request.URL.String()
+	p.header = res.Header
+
+	for _, l := range discover.ParseLinkHeader(res.Header.Values("Link"), res.Request.URL) {
+		for _, rel := range l.Rels {
+			if rel == "me" {
+				p.links = append(p.links, l.URL)
+			}
+		}
+	}
+
+	d := mf.Parse(res.Body, res.Request.URL)
+	p.links = append(p.links, d.Rels["me"]...)
+
+	return &p, nil
+}
+
+// findLink returns the first of the links that matches any of the targets
+func findLink(links, targets []string) (string, bool) {
+	for _, l := range links {
+		for _, t := range targets {
+			if matches(l, t) {
+				return l, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matches reports whether the link points at the target: the URLs are
+// equivalent, and either their schemes are the same or an http link is
+// upgraded to an https target. An https link never matches an http target,
+// just as an https page never redirects to an http one.
+func matches(link, target string) bool {
+	if !urlnorm.Equivalent(link, target) {
+		return false
+	}
+
+	l, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	t, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	ls, ts := strings.ToLower(l.Scheme), strings.ToLower(t.Scheme)
+	return ls == ts || ls == "http" && ts == "https"
+}
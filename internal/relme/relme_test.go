@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package relme
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	mux := http.NewServeMux()
+	page := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>test</title></head><body>%s</body></html>", body)
+		}
+	}
+	mux.Handle("/jane", page(`<a rel="me" href="/profile/jane">elsewhere</a>`))
+	mux.Handle("/profile/jane", page(`<a rel="me" href="/jane/">home</a>`))
+	mux.Handle("/lonely", page(`<a rel="me" href="/profile/jane">elsewhere</a>`))
+	mux.Handle("/moved", http.RedirectHandler("/jane", http.StatusMovedPermanently))
+	mux.Handle("/temporary", http.RedirectHandler("/profile/jane", http.StatusFound))
+	mux.HandleFunc("/header", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Link", `</profile/bob>; rel="me"`)
+		fmt.Fprint(w, "<!DOCTYPE html><html><body></body></html>")
+	})
+	mux.Handle("/profile/bob", page(`<link rel="me" href="/HEADER#top">`))
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	tests := map[string]struct {
+		from     string
+		to       string
+		forward  bool
+		backward bool
+		hops     int
+	}{
+		"both ways":          {"/jane", "/profile/jane", true, true, 2},
+		"one way":            {"/lonely", "/profile/jane", true, false, 1},
+		"reverse":            {"/profile/jane", "/lonely", false, true, 1},
+		"permanent redirect": {"/moved", "/profile/jane", true, true, 3},
+		"temporary redirect": {"/jane", "/temporary", false, true, 2},
+		"link header":        {"/header", "/profile/bob", true, false, 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, _, _, err := Fetch(s.URL+tc.from, s.URL+tc.to)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if r.Forward != tc.forward || r.Backward != tc.backward {
+				t.Fatalf("want forward %v and backward %v, got %v and %v", tc.forward, tc.backward, r.Forward, r.Backward)
+			}
+			if r.Verified != (tc.forward && tc.backward) {
+				t.Fatalf("want verified %v, got %v", tc.forward && tc.backward, r.Verified)
+			}
+			if len(r.Path) != tc.hops {
+				t.Fatalf("want %d hops, got %v", tc.hops, r.Path)
+			}
+		})
+	}
+}
+
+func TestFindLink(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+	}{
+		"trailing slash": {"https://example.com/jane/", "https://example.com/jane"},
+		"empty path":     {"https://example.com", "https://example.com/"},
+		"case":           {"HTTPS://Example.COM/", "https://example.com/"},
+		"default port":   {"http://example.com:80/", "http://example.com/"},
+		"fragment":       {"https://example.com/#me", "https://example.com/"},
+		"http upgraded":  {"http://example.com/jane", "https://example.com/jane"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := findLink([]string{tc.a}, []string{tc.b}); !ok {
+				t.Fatalf("%s and %s should match", tc.a, tc.b)
+			}
+		})
+	}
+
+	mismatches := map[string]struct {
+		a, b string
+	}{
+		"https downgraded": {"https://example.com/jane", "http://example.com/jane"},
+		"other path":       {"https://example.com/jane", "https://example.com/bob"},
+		"other host":       {"https://example.com/jane", "https://example.org/jane"},
+	}
+
+	for name, tc := range mismatches {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := findLink([]string{tc.a}, []string{tc.b}); ok {
+				t.Fatalf("%s and %s should not match", tc.a, tc.b)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package replycontext provides the information needed to display the
+// context of a reply to a page.
+package replycontext
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/authorship"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hentry"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/icon"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/pageinfo"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// iconSize is the size of the site icon to pick, in pixels
+const iconSize = 32
+
+// Context represents the context of a reply
+type Context struct {
+	Source      string `json:"source,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Text        string `json:"text,omitempty"`
+	AuthorName  string `json:"authorName,omitempty"`
+	AuthorPhoto string `json:"authorPhoto,omitempty"`
+	Published   string `json:"published,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+	SiteIcon    string `json:"siteIcon,omitempty"`
+}
+
+// Fetch fetches the page at URI and returns its reply context, together
+// with the response header.
+func Fetch(uri string) (*Context, *http.Header, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := FromDocument(d, res.Request.URL)
+	if c.Name == "" && c.Text == "" {
+		return nil, &res.Header, fmt.Errorf("no reply context found")
+	}
+
+	return &c, &res.Header, nil
+}
+
+// FromDocument returns the reply context of a document retrieved from the
+// given URL. Microformats are preferred, OpenGraph and other page
+// information are used as fallback.
+func FromDocument(d *goquery.Document, u *url.URL) Context {
+	c := Context{Source: u.String()}
+
+	var author *hcard.HCard
+	if e, err := hentry.FromDocument(d, u); err == nil {
+		c.Name = e.PostName
+		c.Published = e.Published
+		if e.Content != nil {
+			c.Text = e.Content.Text
+		}
+		if c.Text == "" {
+			c.Text = e.Summary
+		}
+		author = e.Author
+	}
+
+	if author == nil || author.PName == "" {
+		if a, err := authorship.FromDocument(d, u); err == nil {
+			author = &a.HCard
+		} else if hc, err := hcard.FromDocument(d, u); err == nil {
+			author = hc
+		}
+	}
+	if author != nil {
+		c.AuthorName = author.PName
+		c.AuthorPhoto = author.Photo
+	}
+
+	pi := pageinfo.FromDocument(d, u)
+	if c.Name == "" {
+		c.Name = pi.Title
+	}
+	if c.Text == "" {
+		c.Text = pi.Description
+	}
+	if c.Published == "" {
+		c.Published = pi.Published
+	}
+
+	c.SiteName = pi.SiteName
+	if c.SiteName == "" {
+		c.SiteName = u.Hostname()
+	}
+	c.SiteIcon = siteIcon(d, u)
+
+	return c
+}
+
+// siteIcon returns the URL of the icon of the site the document belongs to,
+// the one that fits iconSize best
+func siteIcon(d *goquery.Document, u *url.URL) string {
+	if icons := icon.Rank(icon.FromDocument(d, u), iconSize); len(icons) > 0 {
+		return icons[0].URL
+	}
+	return ""
+}
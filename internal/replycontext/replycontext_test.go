@@ -0,0 +1,97 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package replycontext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	tests := map[string]struct {
+		link string
+		want Context
+	}{
+		"h-entry": {"/entry.html", Context{
+			Name:        "Walking the dog",
+			Text:        "We went all the way to the lake.",
+			AuthorName:  "Jane Doe",
+			AuthorPhoto: "%s/me.jpg",
+			Published:   "2023-04-01T10:00:00+02:00",
+			SiteName:    "Jane's notebook",
+			SiteIcon:    "%s/icon.png",
+		}},
+		"representative h-card": {"/note.html", Context{
+			Name:        "Just a quick note.",
+			Text:        "Just a quick note.",
+			AuthorName:  "Bob",
+			AuthorPhoto: "%s/bob.png",
+			SiteName:    "127.0.0.1",
+			SiteIcon:    "%s/favicon.ico",
+		}},
+		"author page": {"/author_url.html", Context{
+			Name:        "Lunch at the lake",
+			Text:        "Sandwiches again.",
+			AuthorName:  "Jane Doe",
+			AuthorPhoto: "%s/jane.jpg",
+			SiteName:    "127.0.0.1",
+			SiteIcon:    "%s/favicon.ico",
+		}},
+		"opengraph": {"/og.html", Context{
+			Name:      "A page with no microformats",
+			Text:      "Described by OpenGraph only.",
+			Published: "2023-05-05T05:05:05Z",
+			SiteName:  "Plain Site",
+			SiteIcon:  "%s/favicon.ico",
+		}},
+		"best icon": {"/cdn.html", Context{
+			Name:     "Served with the assets on a CDN.",
+			Text:     "Served with the assets on a CDN.",
+			SiteName: "CDN Site",
+			SiteIcon: "https://cdn.example/x/icon-48.png",
+		}},
+		"favicon of the origin": {"/cdn_favicon.html", Context{
+			Name:     "No icon declared.",
+			Text:     "No icon declared.",
+			SiteName: "CDN Site",
+			SiteIcon: "%s/favicon.ico",
+		}},
+	}
+
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _, err := Fetch(s.URL + tc.link)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			want := tc.want
+			want.Source = s.URL + tc.link
+			want.AuthorPhoto = strings.Replace(want.AuthorPhoto, "%s", s.URL, 1)
+			want.SiteIcon = strings.Replace(want.SiteIcon, "%s", s.URL, 1)
+
+			if *c != want {
+				t.Fatalf("want %+v, got %+v", want, *c)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package twittercard provides handling for Twitter Card metadata.
+package twittercard
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Card represents Twitter Card information, see
+// https://developer.x.com/en/docs/x-for-websites/cards/overview/markup
+type Card struct {
+	Card        string `json:"card,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ImageAlt    string `json:"imageAlt,omitempty"`
+	Site        string `json:"site,omitempty"`
+	Creator     string `json:"creator,omitempty"`
+}
+
+// Fetch fetches the page at URI and returns Twitter Card info
+func Fetch(uri string) (*Card, *http.Header, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+
+	res, err := http.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	d, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := FromDocument(d, res.Request.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &c, &res.Header, nil
+}
+
+// FromDocument returns Twitter Card properties from a document retrieved
+// from the given URL. The image URL is resolved against the base of the
+// document, and dropped if it isn't a valid absolute http(s) URL.
+func FromDocument(d *goquery.Document, u *url.URL) (Card, error) {
+	var c Card
+	found := false
+
+	d.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		name, ok := s.Attr("name")
+		if !ok {
+			name, ok = s.Attr("property")
+		}
+		content, hasContent := s.Attr("content")
+		if !ok || !hasContent {
+			return
+		}
+
+		if c.set(strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(content)) {
+			found = true
+		}
+	})
+
+	if !found {
+		return Card{}, fmt.Errorf("no twitter card properties found")
+	}
+
+	c.Image = urlnorm.Resolve(urlnorm.Base(d, u), c.Image)
+	return c, nil
+}
+
+// set sets the property of Twitter Card information and tells whether it is
+// a known one; the first value of a property wins
+func (c *Card) set(name, content string) bool {
+	var field *string
+	switch name {
+	case "twitter:card":
+		field = &c.Card
+	case "twitter:title":
+		field = &c.Title
+	case "twitter:description":
+		field = &c.Description
+	case "twitter:image", "twitter:image:src":
+		field = &c.Image
+	case "twitter:image:alt":
+		field = &c.ImageAlt
+	case "twitter:site":
+		field = &c.Site
+	case "twitter:creator":
+		field = &c.Creator
+	default:
+		return false
+	}
+
+	if *field == "" {
+		*field = content
+	}
+	return true
+}
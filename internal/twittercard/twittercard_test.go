@@ -0,0 +1,60 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package twittercard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestFetch(t *testing.T) {
+	fs := http.FileServer(http.Dir("testdata"))
+	s := httptest.NewServer(fs)
+	defer s.Close()
+
+	got, _, err := Fetch(s.URL + "/card.html")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	want := Card{
+		Card:        "summary_large_image",
+		Title:       "Walking the dog",
+		Description: "A short story about a long walk.",
+		Image:       s.URL + "/dog.jpg",
+		ImageAlt:    "A dog on a lead",
+		Site:        "@janeblog",
+		Creator:     "@jane",
+	}
+	if *got != want {
+		t.Fatalf("want %+v, got %+v", want, *got)
+	}
+}
+
+func TestNoCard(t *testing.T) {
+	d, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head><meta property="og:title" content="OpenGraph only"></head></html>`))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if _, err := FromDocument(d, nil); err == nil {
+		t.Fatalf("want error for a page without Twitter Card properties")
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package urlnorm provides URL normalization and comparison.
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/idna"
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Normalize returns the URL normalized as per RFC 3986: scheme and host are
+// lowercased, internationalized host names are converted to punycode,
+// default ports are removed, empty path is replaced with "/", dot segments
+// are removed, and percent-encoding is normalized. The fragment is dropped,
+// since it doesn't identify a different resource.
+func Normalize(link string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Opaque != "" {
+		return u.Scheme + ":" + u.Opaque, nil
+	}
+
+	host, err := normalizeHost(u)
+	if err != nil {
+		return "", err
+	}
+
+	p := removeDotSegments(normalizePercent(u.EscapedPath()))
+	if p == "" && host != "" {
+		p = "/"
+	}
+
+	var b strings.Builder
+	if u.Scheme != "" {
+		b.WriteString(u.Scheme)
+		b.WriteString(":")
+	}
+	if host != "" {
+		b.WriteString("//")
+		if u.User != nil {
+			b.WriteString(u.User.String())
+			b.WriteString("@")
+		}
+		b.WriteString(host)
+	}
+	b.WriteString(p)
+	if u.RawQuery != "" {
+		b.WriteString("?")
+		b.WriteString(normalizePercent(u.RawQuery))
+	}
+
+	return b.String(), nil
+}
+
+// Base returns the URL relative references in a document retrieved from the
+// given URL are resolved against: the <base href> of the document if there is
+// one, u otherwise. u may be nil if the URL of the document is unknown.
+func Base(d *goquery.Document, u *url.URL) *url.URL {
+	if u == nil {
+		u = &url.URL{}
+	}
+	if href, ok := d.Find("base[href]").First().Attr("href"); ok {
+		if b, err := u.Parse(strings.TrimSpace(href)); err == nil {
+			return b
+		}
+	}
+	return u
+}
+
+// Resolve returns the reference resolved against the base URL, or the empty
+// string if the result is not a valid absolute http(s) URL.
+func Resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	if base == nil {
+		base = &url.URL{}
+	}
+
+	u, err := base.Parse(ref)
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+// Equivalent reports whether two URLs refer to the same page: they are the
+// same after normalization, with no regard to http and https schemes and
+// trailing slashes in the path. Empty strings are not equivalent to anything.
+func Equivalent(a, b string) bool {
+	if strings.TrimSpace(a) == "" || strings.TrimSpace(b) == "" {
+		return false
+	}
+
+	ka, err := key(a)
+	if err != nil {
+		return false
+	}
+	kb, err := key(b)
+	if err != nil {
+		return false
+	}
+	return ka == kb
+}
+
+// key returns the string to compare the URL by
+func key(link string) (string, error) {
+	n, err := Normalize(link)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(n)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "http"
+	}
+	u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/")
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return u.String(), nil
+}
+
+func normalizeHost(u *url.URL) (string, error) {
+	host, port := u.Hostname(), u.Port()
+	if host == "" {
+		return "", nil
+	}
+
+	host = strings.ToLower(host)
+	if net.ParseIP(host) == nil {
+		ascii, err := idna.Lookup.ToASCII(host)
+		if err != nil {
+			return "", err
+		}
+		host = ascii
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	if port == "" || port == defaultPorts[u.Scheme] {
+		return host, nil
+	}
+	return host + ":" + port, nil
+}
+
+// normalizePercent uppercases the percent-encoded octets and decodes the ones
+// that encode unreserved characters
+func normalizePercent(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+// removeDotSegments implements the algorithm of RFC 3986, section 5.2.4
+func removeDotSegments(p string) string {
+	var out []string
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, "../"):
+			p = p[3:]
+		case strings.HasPrefix(p, "./"):
+			p = p[2:]
+		case strings.HasPrefix(p, "/./"):
+			p = p[2:]
+		case p == "/.":
+			p = "/"
+		case strings.HasPrefix(p, "/../"):
+			p = p[3:]
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case p == "/..":
+			p = "/"
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case p == "." || p == "..":
+			p = ""
+		default:
+			start := 0
+			if p[0] == '/' {
+				start = 1
+			}
+			end := strings.Index(p[start:], "/")
+			if end < 0 {
+				end = len(p)
+			} else {
+				end += start
+			}
+			out = append(out, p[:end])
+			p = p[end:]
+		}
+	}
+	return strings.Join(out, "")
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+func isUnreserved(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package urlnorm
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]struct {
+		link string
+		want string
+	}{
+		"already normal":     {"https://example.com/", "https://example.com/"},
+		"empty path":         {"https://example.com", "https://example.com/"},
+		"scheme case":        {"HTTP://example.com/", "http://example.com/"},
+		"host case":          {"http://Example.COM/", "http://example.com/"},
+		"path case kept":     {"http://example.com/Jane", "http://example.com/Jane"},
+		"default http port":  {"http://example.com:80/", "http://example.com/"},
+		"default https port": {"https://example.com:443/a", "https://example.com/a"},
+		"other port":         {"http://example.com:8080", "http://example.com:8080/"},
+		"https on port 80":   {"https://example.com:80/", "https://example.com:80/"},
+		"empty port":         {"http://example.com:/", "http://example.com/"},
+		"dot segments":       {"http://example.com/a/./b/../c", "http://example.com/a/c"},
+		"leading dots":       {"http://example.com/../a", "http://example.com/a"},
+		"trailing dot":       {"http://example.com/a/b/..", "http://example.com/a/"},
+		"percent case":       {"http://example.com/%e2%82%ac", "http://example.com/%E2%82%AC"},
+		"unreserved decoded": {"http://example.com/%7Ejane%2D%41", "http://example.com/~jane-A"},
+		"reserved kept":      {"http://example.com/a%2Fb", "http://example.com/a%2Fb"},
+		"query kept":         {"http://example.com/?b=2&a=1", "http://example.com/?b=2&a=1"},
+		"query percent":      {"http://example.com/?q=%7euser", "http://example.com/?q=~user"},
+		"empty query":        {"http://example.com/?", "http://example.com/"},
+		"fragment dropped":   {"http://example.com/page#top", "http://example.com/page"},
+		"idn":                {"https://пример.рф/", "https://xn--e1afmkfd.xn--p1ai/"},
+		"idn upper case":     {"https://ПРИМЕР.РФ", "https://xn--e1afmkfd.xn--p1ai/"},
+		"punycode kept":      {"https://xn--e1afmkfd.xn--p1ai/", "https://xn--e1afmkfd.xn--p1ai/"},
+		"ipv4":               {"http://127.0.0.1:80/", "http://127.0.0.1/"},
+		"ipv6":               {"http://[::1]:8080/", "http://[::1]:8080/"},
+		"userinfo":           {"http://user@Example.com", "http://user@example.com/"},
+		"surrounding spaces": {"  https://example.com/  ", "https://example.com/"},
+		"mailto":             {"MAILTO:jane@example.com", "mailto:jane@example.com"},
+		"relative":           {"/a/../b", "/b"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Normalize(tc.link)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"identical":          {"https://example.com/", "https://example.com/", true},
+		"empty path":         {"https://example.com", "https://example.com/", true},
+		"case":               {"HTTP://Example.com/", "http://example.com/", true},
+		"http and https":     {"http://example.com/", "https://example.com/", true},
+		"trailing slash":     {"https://example.com/jane/", "https://example.com/jane", true},
+		"default port":       {"https://example.com:443", "http://example.com:80/", true},
+		"fragment":           {"https://example.com/#me", "https://example.com/", true},
+		"idn":                {"https://пример.рф", "https://xn--e1afmkfd.xn--p1ai/", true},
+		"percent-encoding":   {"https://example.com/%7Ejane", "https://example.com/~jane", true},
+		"different host":     {"https://example.com/", "https://example.org/", false},
+		"different path":     {"https://example.com/a", "https://example.com/b", false},
+		"path case":          {"https://example.com/Jane", "https://example.com/jane", false},
+		"different query":    {"https://example.com/?a=1", "https://example.com/?a=2", false},
+		"different port":     {"https://example.com:8443/", "https://example.com/", false},
+		"subdomain":          {"https://www.example.com/", "https://example.com/", false},
+		"other scheme":       {"ftp://example.com/", "http://example.com/", false},
+		"unparseable":        {"http://[::1", "http://[::1", false},
+		"slash before query": {"https://example.com/a/?x=1", "https://example.com/a?x=1", true},
+		"empty":              {"", "", false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Equivalent(tc.a, tc.b); got != tc.want {
+				t.Fatalf("Equivalent(%s, %s): want %v, got %v", tc.a, tc.b, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := map[string]struct {
+		base string
+		html string
+		ref  string
+		want string
+	}{
+		"absolute":          {"https://example.com/a/b", "", "https://other.example/x", "https://other.example/x"},
+		"relative":          {"https://example.com/a/b", "", "c.png", "https://example.com/a/c.png"},
+		"root relative":     {"https://example.com/a/b", "", "/c.png", "https://example.com/c.png"},
+		"protocol relative": {"https://example.com/a/b", "", "//cdn.example/c.png", "https://cdn.example/c.png"},
+		"base href":         {"https://example.com/a/b", `<base href="https://cdn.example/assets/">`, "c.png", "https://cdn.example/assets/c.png"},
+		"relative base":     {"https://example.com/a/b", `<base href="/assets/">`, "c.png", "https://example.com/assets/c.png"},
+		"no URL":            {"", "", "c.png", ""},
+		"no URL with base":  {"", `<base href="https://cdn.example/">`, "c.png", "https://cdn.example/c.png"},
+		"javascript":        {"https://example.com/", "", "javascript:alert(1)", ""},
+		"data":              {"https://example.com/", "", "data:image/png;base64,iVBORw0KGgo=", ""},
+		"ftp":               {"https://example.com/", "", "ftp://example.com/c.png", ""},
+		"empty":             {"https://example.com/", "", " ", ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + tc.html + "</head></html>"))
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			var u *url.URL
+			if tc.base != "" {
+				u, _ = url.Parse(tc.base)
+			}
+
+			if got := Resolve(Base(d, u), tc.ref); got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2026 Evgeny Kuznetsov (evgeny@kuznetsov.md)
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package vcard provides conversion of h-cards to vCard 4 (RFC 6350) and
+// jCard (RFC 7095).
+package vcard
+
+import (
+	"sort"
+	"strings"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/contentline"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+)
+
+// ContentType is the media type of vCard
+const ContentType = "text/vcard"
+
+// property is a single vCard property
+type property struct {
+	name   string
+	params map[string]string
+	kind   string   // jCard value type
+	values []string // list of values, or the components of a structured value
+	// structured tells the values are the components of a single value
+	structured bool
+}
+
+// Encode returns the vCard representation of the h-card
+func Encode(hc *hcard.HCard) []byte {
+	var b strings.Builder
+	contentline.Write(&b, "BEGIN:VCARD")
+	for _, p := range properties(hc) {
+		contentline.Write(&b, p.String())
+	}
+	contentline.Write(&b, "END:VCARD")
+	return []byte(b.String())
+}
+
+// JCard returns the jCard representation of the h-card, ready to be
+// marshalled to JSON
+func JCard(hc *hcard.HCard) []interface{} {
+	props := []interface{}{}
+	for _, p := range properties(hc) {
+		params := map[string]string{}
+		for k, v := range p.params {
+			params[strings.ToLower(k)] = v
+		}
+		prop := []interface{}{strings.ToLower(p.name), params, p.kind}
+		if p.structured {
+			prop = append(prop, p.values)
+		} else {
+			for _, v := range p.values {
+				prop = append(prop, v)
+			}
+		}
+		props = append(props, prop)
+	}
+	return []interface{}{"vcard", props}
+}
+
+// properties returns the vCard properties of the h-card
+func properties(hc *hcard.HCard) (props []property) {
+	add := func(name, kind string, values ...string) {
+		for _, v := range values {
+			if v != "" {
+				props = append(props, property{name: name, kind: kind, values: []string{v}})
+			}
+		}
+	}
+
+	add("VERSION", "text", "4.0")
+	props = append(props, property{name: "FN", kind: "text", values: []string{formattedName(hc)}})
+	add("NICKNAME", "text", hc.Nickname)
+	add("NOTE", "text", hc.Note)
+
+	if len(hc.Photos) == 0 {
+		add("PHOTO", "uri", hc.Photo)
+	}
+	for _, p := range hc.Photos {
+		add("PHOTO", "uri", p.Value)
+	}
+
+	add("URL", "uri", hc.URL...)
+	if len(hc.UID) > 0 {
+		add("UID", "uri", hc.UID[0])
+	}
+	for _, e := range hc.Email {
+		add("EMAIL", "text", strings.TrimPrefix(e, "mailto:"))
+	}
+	add("TEL", "text", hc.Tel...)
+	for _, o := range hc.Org {
+		add("ORG", "text", o.PName)
+	}
+	add("TITLE", "text", hc.JobTitle...)
+
+	adrs := hc.Adr
+	if len(adrs) == 0 && (hc.Locality != "" || hc.Region != "" || hc.CountryName != "") {
+		adrs = []hcard.Adr{{Locality: hc.Locality, Region: hc.Region, CountryName: hc.CountryName}}
+	}
+	for _, a := range adrs {
+		props = append(props, address(a))
+	}
+
+	add("BDAY", "date-and-or-time", hc.Bday)
+	// PRONOUNS is defined in RFC 9554
+	add("PRONOUNS", "text", hc.Pronouns...)
+	if len(hc.Category) > 0 {
+		props = append(props, property{name: "CATEGORIES", kind: "text", values: hc.Category})
+	}
+	add("KEY", "uri", hc.Key...)
+	add("SOURCE", "uri", hc.Source)
+
+	return
+}
+
+// formattedName returns the name to use for the mandatory FN property
+func formattedName(hc *hcard.HCard) string {
+	switch {
+	case hc.PName != "":
+		return hc.PName
+	case hc.Nickname != "":
+		return hc.Nickname
+	case len(hc.URL) > 0:
+		return hc.URL[0]
+	}
+	return ""
+}
+
+// address returns the ADR property of the h-adr
+func address(a hcard.Adr) property {
+	p := property{
+		name:       "ADR",
+		kind:       "text",
+		values:     []string{"", "", a.StreetAddress, a.Locality, a.Region, a.PostalCode, a.CountryName},
+		structured: true,
+	}
+	if a.Label != "" {
+		p.params = map[string]string{"LABEL": a.Label}
+	}
+	return p
+}
+
+// String returns the unfolded vCard content line of the property
+func (p property) String() string {
+	var b strings.Builder
+	b.WriteString(p.name)
+
+	keys := make([]string, 0, len(p.params))
+	for k := range p.params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(";" + k + "=\"" + contentline.EscapeParam(p.params[k]) + "\"")
+	}
+
+	b.WriteString(":")
+	values := make([]string, len(p.values))
+	for i, v := range p.values {
+		values[i] = v
+		if p.kind != "uri" {
+			values[i] = contentline.EscapeText(v)
+		}
+	}
+	sep := ","
+	if p.structured {
+		sep = ";"
+	}
+	b.WriteString(strings.Join(values, sep))
+
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package vcard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"evgenykuznetsov.org/go/indieweb-glue/internal/contentline"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+)
+
+var jane = &hcard.HCard{
+	Source:   "https://jane.example/",
+	PName:    "Jane Doe",
+	Note:     "Hi; I'm Jane, really.",
+	Photos:   []hcard.Photo{{Value: "https://jane.example/jane.jpg", Alt: "Jane"}},
+	URL:      []string{"https://jane.example/", "https://social.example/@jane"},
+	UID:      []string{"https://jane.example/"},
+	Email:    []string{"mailto:jane@example.com"},
+	Org:      []*hcard.HCard{{PName: "ACME"}},
+	Adr:      []hcard.Adr{{Label: "Home", StreetAddress: "742 Evergreen Terrace", Locality: "Springfield", PostalCode: "97403"}},
+	Category: []string{"cycling", "photography"},
+}
+
+func TestEncode(t *testing.T) {
+	want := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:Jane Doe",
+		`NOTE:Hi\; I'm Jane\, really.`,
+		"PHOTO:https://jane.example/jane.jpg",
+		"URL:https://jane.example/",
+		"URL:https://social.example/@jane",
+		"UID:https://jane.example/",
+		"EMAIL:jane@example.com",
+		"ORG:ACME",
+		`ADR;LABEL="Home":;;742 Evergreen Terrace;Springfield;;97403;`,
+		"CATEGORIES:cycling,photography",
+		"SOURCE:https://jane.example/",
+		"END:VCARD",
+		"",
+	}, "\r\n")
+
+	if got := string(Encode(jane)); got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	note := strings.Repeat("Ж", 100)
+	got := string(Encode(&hcard.HCard{PName: "Jane", Note: note}))
+
+	var unfolded strings.Builder
+	for _, l := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+		if len(l) > contentline.MaxLength {
+			t.Fatalf("line longer than %d octets: %q", contentline.MaxLength, l)
+		}
+		if strings.HasPrefix(l, " ") {
+			unfolded.WriteString(l[1:])
+			continue
+		}
+		unfolded.WriteString("\n" + l)
+	}
+
+	if !strings.Contains(unfolded.String(), "\nNOTE:"+note+"\n") {
+		t.Fatalf("want note %q preserved, got %q", note, got)
+	}
+}
+
+func TestJCard(t *testing.T) {
+	want := `["vcard",[["version",{},"text","4.0"],["fn",{},"text","Jane Doe"],["note",{},"text","Hi; I'm Jane, really."],` +
+		`["photo",{},"uri","https://jane.example/jane.jpg"],["url",{},"uri","https://jane.example/"],` +
+		`["url",{},"uri","https://social.example/@jane"],["uid",{},"uri","https://jane.example/"],` +
+		`["email",{},"text","jane@example.com"],["org",{},"text","ACME"],` +
+		`["adr",{"label":"Home"},"text",["","","742 Evergreen Terrace","Springfield","","97403",""]],` +
+		`["categories",{},"text","cycling","photography"],["source",{},"uri","https://jane.example/"]]]`
+
+	got, err := json.Marshal(JCard(jane))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("want %s, got %s", want, got)
+	}
+}
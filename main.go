@@ -22,23 +22,64 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"mime"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"os/signal"
 	"path"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"evgenykuznetsov.org/go/indieweb-glue/internal/authorship"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/discover"
 	"evgenykuznetsov.org/go/indieweb-glue/internal/hcard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hentry"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hevent"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hfeed"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hproduct"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hrecipe"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/hreview"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/ical"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/icon"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/jf2"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/jsonld"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/manifest"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/mf2"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/oembed"
 	"evgenykuznetsov.org/go/indieweb-glue/internal/og"
 	"evgenykuznetsov.org/go/indieweb-glue/internal/pageinfo"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/posttype"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/relme"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/replycontext"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/twittercard"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/urlnorm"
+	"evgenykuznetsov.org/go/indieweb-glue/internal/vcard"
 	"github.com/memcachier/mc/v3"
 )
 
 //go:embed tpl/*
 var tpl embed.FS
 
+const (
+	defaultFeedLimit = 10
+	maxFeedLimit     = 100
+	maxFeedPages     = 5
+	maxHcardHops     = 3
+	maxEmbedSize     = 4096
+	defaultIconSize  = 32
+	maxIconSize      = 1024
+
+	jcardContentType     = "application/vcard+json"
+	oembedXMLContentType = "text/xml"
+)
+
+// embedSizeParams are the oEmbed maxwidth and maxheight parameters
+var embedSizeParams = []intFormParam{{"maxwidth", 0, maxEmbedSize}, {"maxheight", 0, maxEmbedSize}}
+
 var websiteUrl string
 
 func calculateExpiration(h, hd http.Header) (bool, time.Time) {
@@ -57,15 +98,6 @@ func calculateExpiration(h, hd http.Header) (bool, time.Time) {
 	return true, expHd
 }
 
-func containsStr(ss []string, s string) bool {
-	for _, v := range ss {
-		if v == s {
-			return true
-		}
-	}
-	return false
-}
-
 func copyHeader(m map[string][]string, w http.ResponseWriter, h string) {
 	h = http.CanonicalHeaderKey(h)
 	if vv, ok := m[h]; ok {
@@ -77,7 +109,7 @@ func copyHeader(m map[string][]string, w http.ResponseWriter, h string) {
 }
 
 func getPhoto(c cache, link string) ([]byte, map[string][]string, error) {
-	key := "photo=" + link
+	key := cacheKey("photo", link)
 	content, exp := c.get(key)
 	if content != nil {
 		fmt.Printf("photo %s cache hit\n", link)
@@ -97,6 +129,10 @@ func getPhoto(c cache, link string) ([]byte, map[string][]string, error) {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		return bb, hd, fmt.Errorf("%s returned %s", link, res.Status)
+	}
+
 	bb, err = io.ReadAll(res.Body)
 	if err != nil {
 		return bb, hd, err
@@ -151,26 +187,104 @@ func serveJSON(c cache, cachePrefix string, g getter) func(http.ResponseWriter,
 		}
 
 		content, hd := getJSON(c, cachePrefix, req.Form["url"][0], g)
+		writeJSON(w, content, hd)
+	}
+}
 
+// writeJSON writes JSON response returned from getter
+func writeJSON(w http.ResponseWriter, content []byte, hd map[string][]string) {
+	if content == nil {
+		http.Error(w, "failed to get info", http.StatusInternalServerError)
+		return
+	}
+
+	setResponseHeaders(w, hd)
+
+	if string(content) == `{}` {
+		http.Error(w, "no appropriate info at URL", http.StatusNotFound)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(content)
+}
+
+// serveContent serves the content of the given type returned from getter,
+// caches it as needed; the empty content means there is no appropriate info
+func serveContent(c cache, cachePrefix, contentType string, g getter) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if len(req.Form["url"]) < 1 {
+			http.Error(w, "no URL specified", http.StatusBadRequest)
+			return
+		}
+
+		content, hd := getJSON(c, cachePrefix, req.Form["url"][0], g)
 		if content == nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, "failed to get info", http.StatusInternalServerError)
 			return
 		}
 
 		setResponseHeaders(w, hd)
 
-		if string(content) == `{}` {
+		if len(content) == 0 {
 			http.Error(w, "no appropriate info at URL", http.StatusNotFound)
+			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Type", contentType)
 		_, _ = w.Write(content)
 	}
 }
 
+// serveFormats serves JSON response returned from the getter for the format
+// requested by the "format" form parameter; the getter for the empty format
+// is the default one
+func serveFormats(c cache, cachePrefix string, getters map[string]getter) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		format := req.Form.Get("format")
+		g, ok := getters[format]
+		if !ok {
+			http.Error(w, "unsupported format", http.StatusBadRequest)
+			return
+		}
+
+		prefix := cachePrefix
+		if format != "" {
+			prefix = fmt.Sprintf("%s-%s", cachePrefix, format)
+		}
+		serveJSON(c, prefix, g)(w, req)
+	}
+}
+
+// cacheKey returns the cache key for the link, normalized so that the
+// equivalent URLs share the cache entry
+func cacheKey(prefix, link string) string {
+	return fmt.Sprintf("%s=%s", prefix, normalizeLink(link))
+}
+
+// normalizeLink returns the normalized URL, or the link itself if it can't
+// be normalized
+func normalizeLink(link string) string {
+	if n, err := urlnorm.Normalize(link); err == nil {
+		return n
+	}
+	return link
+}
+
 // getJSON gets JSON response returned from getter, caches it as needed
 func getJSON(c cache, cachePrefix, link string, g getter) (content []byte, hd map[string][]string) {
-	key := fmt.Sprintf("%s=%s", cachePrefix, link)
+	key := cacheKey(cachePrefix, link)
 	content, exp := c.get(key)
 	if content != nil {
 		fmt.Printf("%s %s cache hit\n", cachePrefix, link)
@@ -190,6 +304,187 @@ func getJSON(c cache, cachePrefix, link string, g getter) (content []byte, hd ma
 	return
 }
 
+// serveHfeed serves the H-Feed JSON, taking the number of entries and pages
+// to follow into account
+func serveHfeed(c cache) func(http.ResponseWriter, *http.Request) {
+	params := []intFormParam{{"limit", defaultFeedLimit, maxFeedLimit}, {"pages", 1, maxFeedPages}}
+	return serveIntParams(params, func(v []int) func(http.ResponseWriter, *http.Request) {
+		limit, pages := v[0], v[1]
+		return serveJSON(c, fmt.Sprintf("hfeed-%d-%d", limit, pages), getHfeed(limit, pages))
+	})
+}
+
+// serveOembed serves the oEmbed JSON of a third-party provider, passing the
+// maximum width and height requested through
+func serveOembed(c cache) func(http.ResponseWriter, *http.Request) {
+	return serveIntParams(embedSizeParams, func(v []int) func(http.ResponseWriter, *http.Request) {
+		maxWidth, maxHeight := v[0], v[1]
+		return serveJSON(c, fmt.Sprintf("oembed-%d-%d", maxWidth, maxHeight), getOembed(maxWidth, maxHeight))
+	})
+}
+
+// serveOembedProvider serves the oEmbed response for the page in the format
+// requested, fitting the maximum width and height requested
+func serveOembedProvider(c cache) func(http.ResponseWriter, *http.Request) {
+	return serveIntParams(embedSizeParams, func(v []int) func(http.ResponseWriter, *http.Request) {
+		maxWidth, maxHeight := v[0], v[1]
+		return func(w http.ResponseWriter, req *http.Request) {
+			switch req.Form.Get("format") {
+			case "", "json":
+				cachePrefix := fmt.Sprintf("oembed-provider-%d-%d", maxWidth, maxHeight)
+				serveJSON(c, cachePrefix, getOembedProvider(maxWidth, maxHeight))(w, req)
+			case "xml":
+				cachePrefix := fmt.Sprintf("oembed-provider-xml-%d-%d", maxWidth, maxHeight)
+				serveContent(c, cachePrefix, oembedXMLContentType, getOembedProviderXML(maxWidth, maxHeight))(w, req)
+			default:
+				http.Error(w, "unsupported format", http.StatusNotImplemented)
+			}
+		}
+	})
+}
+
+// serveHcard serves the H-Card JSON, following the number of rel=author and
+// rel=me links requested if there is no representative h-card on the page
+func serveHcard(c cache) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hops, err := intParam(req.Form, "follow", 0, maxHcardHops)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cachePrefix := "hcard"
+		if hops > 0 {
+			cachePrefix = fmt.Sprintf("hcard-follow%d", hops)
+		}
+
+		w.Header().Add("Vary", "Accept")
+		switch format := req.Form.Get("format"); {
+		case format == "vcf", format == "" && accepts(req, vcard.ContentType):
+			serveContent(c, cachePrefix+"-vcf", vcard.ContentType, getHcardVCF(hops))(w, req)
+		case format == "jcard":
+			serveContent(c, cachePrefix+"-jcard", jcardContentType, getHcardJCard(hops))(w, req)
+		default:
+			serveFormats(c, cachePrefix, map[string]getter{"": getHcardFollowing(hops), "jf2": getHcardJF2Following(hops)})(w, req)
+		}
+	}
+}
+
+// serveHevent serves the H-Event JSON, or the iCalendar if requested
+func serveHevent(c cache) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept")
+		if format := req.Form.Get("format"); format == "ics" || format == "" && accepts(req, ical.ContentType) {
+			serveContent(c, "hevent-ics", ical.ContentType, getHeventICS)(w, req)
+			return
+		}
+		serveFormats(c, "hevent", map[string]getter{"": getHevent})(w, req)
+	}
+}
+
+// accepts reports whether the Accept header of the request explicitly lists
+// the media type
+func accepts(req *http.Request, mediaType string) bool {
+	for _, h := range req.Header.Values("Accept") {
+		for _, r := range strings.Split(h, ",") {
+			mt, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+			if err == nil && strings.EqualFold(mt, mediaType) && params["q"] != "0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// serveRelMe serves the JSON with the result of rel=me verification between
+// two URLs
+func serveRelMe(c cache) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		from, to := req.Form.Get("from"), req.Form.Get("to")
+		if from == "" || to == "" {
+			http.Error(w, "both from and to URLs must be specified", http.StatusBadRequest)
+			return
+		}
+
+		content, hd := getJSON(c, "relme", relMeKey(from, to), func(string) ([]byte, map[string][]string) {
+			return getRelMe(from, to)
+		})
+		writeJSON(w, content, hd)
+	}
+}
+
+// relMeKey returns the link to cache the verification between two URLs by
+func relMeKey(from, to string) string {
+	return url.Values{"from": {normalizeLink(from)}, "to": {normalizeLink(to)}}.Encode()
+}
+
+// intFormParam describes a positive integer form parameter: its name, its
+// default value and its maximum value
+type intFormParam struct {
+	name     string
+	def, max int
+}
+
+// serveIntParams parses the positive integer form parameters of the request
+// and serves it with the handler built for their values, in the order the
+// parameters are listed
+func serveIntParams(params []intFormParam, h func([]int) func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		values := make([]int, len(params))
+		for i, p := range params {
+			values[i], err = intParam(req.Form, p.name, p.def, p.max)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		h(values)(w, req)
+	}
+}
+
+// intParam returns the positive integer value of the form parameter, def if
+// the parameter is not set, or max if the value is greater than max
+func intParam(form map[string][]string, name string, def, max int) (int, error) {
+	if len(form[name]) < 1 {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(form[name][0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+
+	if n > max {
+		return max, nil
+	}
+	return n, nil
+}
+
 func serveInfo(w http.ResponseWriter, req *http.Request) {
 	fp := path.Join("tpl", "index.html")
 	tmpl, err := template.ParseFS(tpl, fp)
@@ -251,6 +546,73 @@ func servePhoto(c cache) func(http.ResponseWriter, *http.Request) {
 	}
 }
 
+// serveIcon serves the icon of the site the page belongs to that fits the
+// requested size best, trying the next best one if an icon can't be fetched
+func serveIcon(c cache) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if len(req.Form["url"]) < 1 {
+			http.Error(w, "no URL specified", http.StatusBadRequest)
+			return
+		}
+
+		size, err := intParam(req.Form, "size", defaultIconSize, maxIconSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		js, ihd := getJSON(c, "icons", req.Form["url"][0], getIcons)
+		var icons []icon.Icon
+		if err := json.Unmarshal(js, &icons); err != nil {
+			http.Error(w, "no icon", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		for _, i := range icon.Rank(icons, size) {
+			bb, hd, err := getPhoto(c, i.URL)
+			if err != nil || len(bb) == 0 || strings.HasPrefix(http.DetectContentType(bb), "text/html") {
+				continue
+			}
+
+			if ok, exp := calculateExpiration(ihd, hd); ok {
+				w.Header().Set("Expires", exp.Format(time.RFC1123))
+				w.Header().Set("Cache-Control", "public")
+			} else {
+				w.Header().Set("Cache-Control", "no-cache")
+			}
+
+			// the cached icons have no Content-Type, some servers don't send
+			// the right one, and sniffing doesn't recognize SVG
+			ct := http.Header(hd).Get("Content-Type")
+			if !strings.HasPrefix(ct, "image/") {
+				ct = i.Type
+			}
+			if ct == "" && bytes.Contains(bb, []byte("<svg")) {
+				ct = "image/svg+xml"
+			}
+			if ct != "" {
+				w.Header().Set("Content-Type", ct)
+			}
+			// SVG icons may carry scripts, don't let them run on our origin
+			w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+
+			t := getModTime(hd)
+			http.ServeContent(w, req, "", t, bytes.NewReader(bb))
+			return
+		}
+
+		http.Error(w, "no icon", http.StatusNotFound)
+	}
+}
+
 func cached(c cache, handler func(w http.ResponseWriter, r *http.Request)) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		content, exp := c.get(r.RequestURI)
@@ -309,10 +671,28 @@ func main() {
 		fmt.Println("using memory cache")
 	}
 
-	http.HandleFunc("/api/hcard", serveJSON(c, "hcard", getHcard))
+	http.HandleFunc("/api/hcard", serveHcard(c))
+	http.HandleFunc("/api/hcards", serveJSON(c, "hcards", getHcards))
+	http.HandleFunc("/api/author", serveJSON(c, "author", getAuthor))
+	http.HandleFunc("/api/discover", serveJSON(c, "discover", getEndpoints))
+	http.HandleFunc("/api/hentry", serveFormats(c, "hentry", map[string]getter{"": getHentry, "jf2": getHentryJF2}))
+	http.HandleFunc("/api/hevent", serveHevent(c))
+	http.HandleFunc("/api/hreview", serveJSON(c, "hreview", getHreview))
+	http.HandleFunc("/api/hproduct", serveJSON(c, "hproduct", getHproduct))
+	http.HandleFunc("/api/hrecipe", serveJSON(c, "hrecipe", getHrecipe))
+	http.HandleFunc("/api/hfeed", serveHfeed(c))
+	http.HandleFunc("/api/mf2", serveFormats(c, "mf2", map[string]getter{"": getMf2, "jf2": getMf2JF2}))
 	http.HandleFunc("/api/opengraph", serveJSON(c, "og", getOG))
+	http.HandleFunc("/api/oembed", serveOembed(c))
+	http.HandleFunc("/api/twittercard", serveJSON(c, "twittercard", getTwitterCard))
 	http.HandleFunc("/api/pageinfo", serveJSON(c, "pageinfo", getPageInfo))
+	http.HandleFunc("/api/manifest", serveJSON(c, "manifest", getManifest))
+	http.HandleFunc("/api/posttype", serveJSON(c, "posttype", getPostType))
+	http.HandleFunc("/api/replycontext", serveJSON(c, "replycontext", getReplyContext))
+	http.HandleFunc("/api/relme", serveRelMe(c))
 	http.HandleFunc("/api/photo", servePhoto(c))
+	http.HandleFunc("/api/icon", serveIcon(c))
+	http.HandleFunc("/oembed", serveOembedProvider(c))
 	http.Handle("/", cached(c, serveInfo))
 
 	_ = http.ListenAndServe(":"+port, nil)
@@ -334,15 +714,319 @@ type getter func(uri string) (js []byte, headers map[string][]string)
 
 // getHcard is a getter for H-Cards
 func getHcard(link string) ([]byte, map[string][]string) {
-	hc, hd, err := hcard.Fetch(link)
+	return getHcardFollowing(0)(link)
+}
+
+// getHcardFollowing returns a getter for H-Cards that follows up to hops
+// rel=author and rel=me links if needed
+func getHcardFollowing(hops int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		hc, hd, err := fetchHcard(link, hops)
+		if err != nil {
+			var hdr http.Header
+			hc, hdr = hcard.Empty()
+			hd = &hdr
+		}
+		content, err := json.Marshal(hc)
+		if err != nil {
+			fmt.Println("can't marshal hcard")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// fetchHcard returns the representative H-Card of the page, following up to
+// hops rel=author and rel=me links if needed, or the schema.org Person that
+// represents the page in its JSON-LD if there is no H-Card
+func fetchHcard(link string, hops int) (*hcard.HCard, *http.Header, error) {
+	d, u, hd, err := hcard.FetchPage(link)
 	if err != nil {
-		var hdr http.Header
-		hc, hdr = hcard.Empty()
-		hd = &hdr
+		return nil, hd, err
 	}
-	content, err := json.Marshal(hc)
+
+	hc, err := hcard.FromDocumentFollowing(d, u, hops)
+	if err == nil {
+		return hc, hd, nil
+	}
+	if p := jsonld.FromDocument(d, u).Person(u); p != nil {
+		return p, hd, nil
+	}
+	return nil, hd, err
+}
+
+// getHcards is a getter for all the H-Cards on a page
+func getHcards(link string) ([]byte, map[string][]string) {
+	cards, hd, err := hcard.FetchAll(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(cards)
 	if err != nil {
-		fmt.Println("can't marshal hcard")
+		fmt.Println("failed to marshal hcards")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHcardJF2Following returns a getter for H-Cards in JF2 format that
+// follows up to hops rel=author and rel=me links if needed
+func getHcardJF2Following(hops int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		hc, hd, err := fetchHcard(link, hops)
+		if err != nil {
+			return []byte("{}"), nil
+		}
+		content, err := json.Marshal(jf2.FromHCard(hc))
+		if err != nil {
+			fmt.Println("can't marshal hcard")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// getHcardVCF returns a getter for H-Cards in vCard format that follows up
+// to hops rel=author and rel=me links if needed
+func getHcardVCF(hops int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		hc, hd, err := fetchHcard(link, hops)
+		if err != nil {
+			return []byte{}, nil
+		}
+		return vcard.Encode(hc), *hd
+	}
+}
+
+// getHcardJCard returns a getter for H-Cards in jCard format that follows up
+// to hops rel=author and rel=me links if needed
+func getHcardJCard(hops int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		hc, hd, err := fetchHcard(link, hops)
+		if err != nil {
+			return []byte{}, nil
+		}
+		content, err := json.Marshal(vcard.JCard(hc))
+		if err != nil {
+			fmt.Println("can't marshal jcard")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// getAuthor is a getter for post authors
+func getAuthor(link string) ([]byte, map[string][]string) {
+	a, hd, err := authorship.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(a)
+	if err != nil {
+		fmt.Println("failed to marshal author")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getEndpoints is a getter for IndieWeb endpoints
+func getEndpoints(link string) ([]byte, map[string][]string) {
+	e, hd, err := discover.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(e)
+	if err != nil {
+		fmt.Println("failed to marshal endpoints")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHentry is a getter for H-Entries
+func getHentry(link string) ([]byte, map[string][]string) {
+	e, hd, err := hentry.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(e)
+	if err != nil {
+		fmt.Println("failed to marshal hentry")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHentryJF2 is a getter for H-Entries in JF2 format
+func getHentryJF2(link string) ([]byte, map[string][]string) {
+	e, hd, err := hentry.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(jf2.FromMicroformat(e.Microformat()))
+	if err != nil {
+		fmt.Println("failed to marshal hentry")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHevent is a getter for H-Events
+func getHevent(link string) ([]byte, map[string][]string) {
+	e, hd, err := hevent.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(e)
+	if err != nil {
+		fmt.Println("failed to marshal hevent")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHeventICS is a getter for H-Events in iCalendar format
+func getHeventICS(link string) ([]byte, map[string][]string) {
+	e, hd, err := hevent.Fetch(link)
+	if err != nil {
+		return []byte{}, nil
+	}
+	return ical.Encode(e), *hd
+}
+
+// getHreview is a getter for H-Reviews
+func getHreview(link string) ([]byte, map[string][]string) {
+	v, hd, err := hreview.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println("failed to marshal hreview")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHproduct is a getter for H-Products
+func getHproduct(link string) ([]byte, map[string][]string) {
+	v, hd, err := hproduct.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println("failed to marshal hproduct")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getHrecipe is a getter for H-Recipes
+func getHrecipe(link string) ([]byte, map[string][]string) {
+	v, hd, err := hrecipe.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println("failed to marshal hrecipe")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getPostType is a getter for post type and name
+func getPostType(link string) ([]byte, map[string][]string) {
+	e, hd, err := hentry.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(posttype.Discover(e.Microformat()))
+	if err != nil {
+		fmt.Println("failed to marshal post type")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getReplyContext is a getter for reply contexts
+func getReplyContext(link string) ([]byte, map[string][]string) {
+	rc, hd, err := replycontext.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(rc)
+	if err != nil {
+		fmt.Println("failed to marshal reply context")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getRelMe returns JSON-packed result of rel=me verification between two
+// URLs, together with HTTP headers that allow caching it no longer than
+// either of the pages
+func getRelMe(from, to string) ([]byte, map[string][]string) {
+	r, hFrom, hTo, err := relme.Fetch(from, to)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(r)
+	if err != nil {
+		fmt.Println("failed to marshal rel=me verification")
+		return nil, nil
+	}
+
+	hd := map[string][]string{"Cache-Control": {"no-cache"}}
+	if ok, exp := calculateExpiration(*hFrom, *hTo); ok {
+		hd = map[string][]string{
+			"Cache-Control": {"public"},
+			"Expires":       {exp.Format(time.RFC1123)},
+		}
+	}
+	return content, hd
+}
+
+// getHfeed returns a getter for H-Feeds
+func getHfeed(limit, pages int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		f, hd, err := hfeed.Fetch(link, limit, pages)
+		if err != nil {
+			return []byte("{}"), nil
+		}
+		content, err := json.Marshal(f)
+		if err != nil {
+			fmt.Println("failed to marshal hfeed")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// getMf2 is a getter for raw microformats2 data
+func getMf2(link string) ([]byte, map[string][]string) {
+	d, hd, err := mf2.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(d)
+	if err != nil {
+		fmt.Println("failed to marshal mf2")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getMf2JF2 is a getter for microformats2 data in JF2 format
+func getMf2JF2(link string) ([]byte, map[string][]string) {
+	d, hd, err := mf2.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(jf2.FromData(d))
+	if err != nil {
+		fmt.Println("failed to marshal mf2")
 		return nil, *hd
 	}
 	return content, *hd
@@ -362,6 +1046,96 @@ func getOG(link string) ([]byte, map[string][]string) {
 	return content, *hd
 }
 
+// getOembed returns a getter for third-party oEmbed responses
+func getOembed(maxWidth, maxHeight int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		e, hd, err := oembed.Fetch(link, maxWidth, maxHeight)
+		if err != nil {
+			return []byte("{}"), nil
+		}
+		content, err := json.Marshal(e)
+		if err != nil {
+			fmt.Println("failed to marshal oEmbed")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// getOembedProvider returns a getter for our own oEmbed responses
+func getOembedProvider(maxWidth, maxHeight int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		r, hd, err := oembed.Provide(link, maxWidth, maxHeight)
+		if err != nil {
+			return []byte("{}"), nil
+		}
+		content, err := json.Marshal(r)
+		if err != nil {
+			fmt.Println("failed to marshal oEmbed")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// getOembedProviderXML returns a getter for our own oEmbed responses in XML
+func getOembedProviderXML(maxWidth, maxHeight int) getter {
+	return func(link string) ([]byte, map[string][]string) {
+		r, hd, err := oembed.Provide(link, maxWidth, maxHeight)
+		if err != nil {
+			return []byte{}, nil
+		}
+		content, err := oembed.EncodeXML(r)
+		if err != nil {
+			fmt.Println("failed to encode oEmbed XML")
+			return nil, *hd
+		}
+		return content, *hd
+	}
+}
+
+// getIcons is a getter for the candidate icons of a site
+func getIcons(link string) ([]byte, map[string][]string) {
+	icons, hd, err := icon.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(icons)
+	if err != nil {
+		fmt.Println("failed to marshal icons")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getManifest is a getter for web app manifests
+func getManifest(link string) ([]byte, map[string][]string) {
+	m, hd, err := manifest.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(m)
+	if err != nil {
+		fmt.Println("failed to marshal manifest")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
+// getTwitterCard is a getter for Twitter Card
+func getTwitterCard(link string) ([]byte, map[string][]string) {
+	tc, hd, err := twittercard.Fetch(link)
+	if err != nil {
+		return []byte("{}"), nil
+	}
+	content, err := json.Marshal(tc)
+	if err != nil {
+		fmt.Println("failed to marshal Twitter Card")
+		return nil, *hd
+	}
+	return content, *hd
+}
+
 // getPageIngo is a getter for page information
 func getPageInfo(link string) ([]byte, map[string][]string) {
 	pi, hd, err := pageinfo.Fetch(link)
@@ -22,6 +22,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -35,9 +36,11 @@ func TestServe(t *testing.T) {
 		f    func(http.ResponseWriter, *http.Request)
 		want string
 	}{
-		"hcard":    {serveJSON(c, "hcard", getHcard), fmt.Sprintf(`{"source":"%s","pname":"Евгений Кузнецов","nickname":"nekr0z","uphoto":"%s/img/avatar.jpg"}`, ms.URL, ms.URL)},
-		"og":       {serveJSON(c, "og", getOG), `{"title":"DIMV","description":"Личный сайт Евгения Кузнецова"}`},
-		"pageinfo": {serveJSON(c, "pageinfo", getPageInfo), `{"title":"DIMV","description":"Личный сайт Евгения Кузнецова"}`},
+		"hcard":    {serveJSON(c, "hcard", getHcard), wantHcard(ms.URL)},
+		"hcards":   {serveJSON(c, "hcards", getHcards), wantHcards(ms.URL)},
+		"og":       {serveJSON(c, "og", getOG), wantOG},
+		"pageinfo": {serveJSON(c, "pageinfo", getPageInfo), `{"title":"DIMV","url":"https://evgenykuznetsov.org/","description":"Личный сайт Евгения Кузнецова","siteName":"DIMV","themeColor":"#ffffff"}`},
+		"manifest": {serveJSON(c, "manifest", getManifest), wantManifest(ms.URL)},
 		"404":      {serveJSON(c, "none", func(uri string) (js []byte, headers map[string][]string) { return getHcard("none") }), "no appropriate info at URL\n{}"},
 	}
 
@@ -74,6 +77,34 @@ func TestServe(t *testing.T) {
 	}
 }
 
+// wantManifest returns the expected manifest JSON of testdata/index.html
+// served at u
+func wantManifest(u string) string {
+	return fmt.Sprintf(`{"source":"%[1]s/site.webmanifest","name":"DIMV","shortName":"DIMV","themeColor":"#ffffff","backgroundColor":"#ffffff",`+
+		`"icons":[{"src":"%[1]s/android-chrome-192x192.png","sizes":"192x192","type":"image/png"},`+
+		`{"src":"%[1]s/android-chrome-512x512.png","sizes":"512x512","type":"image/png"}]}`, u)
+}
+
+// wantOG is the expected OpenGraph JSON of testdata/index.html
+const wantOG = `{"title":"DIMV","type":"website","url":"https://evgenykuznetsov.org/",` +
+	`"description":"Личный сайт Евгения Кузнецова","siteName":"DIMV"}`
+
+// wantHcard returns the expected representative h-card JSON of
+// testdata/index.html served at u
+func wantHcard(u string) string {
+	return strings.ReplaceAll(`{"source":"%s","check":"rel-me","pname":"Евгений Кузнецов","nickname":"nekr0z","uphoto":"%s/img/avatar.jpg",`+
+		`"photo":[{"value":"%s/img/avatar.jpg","alt":"nekr0z"}],"url":["https://evgenykuznetsov.org"]}`, "%s", u)
+}
+
+// wantHcards returns the expected JSON of all the h-cards of
+// testdata/index.html served at u
+func wantHcards(u string) string {
+	return strings.ReplaceAll(`[{"pname":"Евгений Кузнецов","nickname":"nekr0z","uphoto":"%s/img/avatar.jpg",`+
+		`"photo":[{"value":"%s/img/avatar.jpg","alt":"nekr0z"}],"url":["https://evgenykuznetsov.org"],"context":"top-level"},`+
+		`{"pname":"Евгений Кузнецов","uphoto":"%s/img/avatar.jpg","photo":[{"value":"%s/img/avatar.jpg","alt":"Евгений Кузнецов"}],`+
+		`"url":["https://evgenykuznetsov.org"],"context":"author"}]`, "%s", u)
+}
+
 func TestServeEmptyHcard(t *testing.T) {
 	c := newMemoryCache()
 	s := httptest.NewServer(http.HandlerFunc(serveJSON(c, "hcard", getHcard)))
@@ -97,6 +128,51 @@ func TestServeEmptyHcard(t *testing.T) {
 	}
 }
 
+func TestServeJSONLDHcard(t *testing.T) {
+	c := newMemoryCache()
+	s := httptest.NewServer(http.HandlerFunc(serveHcard(c)))
+	defer s.Close()
+
+	fs := http.FileServer(http.Dir("testdata"))
+	ms := httptest.NewServer(fs)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		format string
+		want   string
+	}{
+		"default": {"", fmt.Sprintf(`{"source":"%s/profile.html","check":"json-ld","pname":"John Smith","url":["https://social.example/@john"]}`, ms.URL)},
+		"jf2":     {"jf2", `{"name":"John Smith","type":"card","url":"https://social.example/@john"}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(s.URL)
+			v := url.Values{}
+			v.Add("url", ms.URL+"/profile.html")
+			if tc.format != "" {
+				v.Add("format", tc.format)
+			}
+			u.RawQuery = v.Encode()
+
+			res, err := http.Get(u.String())
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			if string(b) != tc.want {
+				t.Fatalf("want %s, got %s", tc.want, b)
+			}
+		})
+	}
+}
+
 func TestServePhoto(t *testing.T) {
 	c := newMemoryCache()
 	s := httptest.NewServer(http.HandlerFunc(servePhoto(c)))
@@ -132,6 +208,75 @@ func TestServePhoto(t *testing.T) {
 		}
 	}
 }
+
+func TestServeIcon(t *testing.T) {
+	c := newMemoryCache()
+	s := httptest.NewServer(http.HandlerFunc(serveIcon(c)))
+	defer s.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><head><link rel="icon" href="/missing.png" sizes="64x64">`+
+			`<link rel="icon" href="/icon.svg"><link rel="apple-touch-icon" href="/touch.png"></head></html>`)
+	})
+	mux.HandleFunc("/icon.svg", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	})
+	mux.HandleFunc("/touch.png", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "\x89PNG\r\n\x1a\n")
+	})
+	mux.HandleFunc("/missing.png", http.NotFound)
+	ms := httptest.NewServer(mux)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		size        string
+		status      int
+		contentType string
+		body        string
+	}{
+		"next best":   {"64", http.StatusOK, "image/svg+xml", `<svg xmlns="http://www.w3.org/2000/svg"></svg>`},
+		"exact":       {"180", http.StatusOK, "image/png", "\x89PNG\r\n\x1a\n"},
+		"scalable":    {"1000", http.StatusOK, "image/svg+xml", `<svg xmlns="http://www.w3.org/2000/svg"></svg>`},
+		"bad size":    {"big", http.StatusBadRequest, "text/plain; charset=utf-8", "size must be a positive integer\n"},
+		"no such url": {"", http.StatusNotFound, "text/plain; charset=utf-8", "no icon\n"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := url.Values{}
+			v.Add("url", ms.URL)
+			if name == "no such url" {
+				v.Set("url", "http://127.0.0.1:0/")
+			}
+			if tc.size != "" {
+				v.Add("size", tc.size)
+			}
+			res, err := http.Get(s.URL + "?" + v.Encode())
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.status {
+				t.Fatalf("want status %d, got %d", tc.status, res.StatusCode)
+			}
+			if ct := res.Header.Get("Content-Type"); ct != tc.contentType {
+				t.Fatalf("want content type %s, got %s", tc.contentType, ct)
+			}
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if string(b) != tc.body {
+				t.Fatalf("want %q, got %q", tc.body, b)
+			}
+		})
+	}
+}
+
 func TestCopyHeader(t *testing.T) {
 	hd := map[string][]string{
 		"Etag": {
@@ -161,3 +306,389 @@ func TestCopyHeader(t *testing.T) {
 		t.Fatalf("cache-control header length: want %d, got %d", 2, len(h))
 	}
 }
+
+func TestIntParam(t *testing.T) {
+	tests := map[string]struct {
+		value   []string
+		want    int
+		wantErr bool
+	}{
+		"unset":    {nil, 10, false},
+		"set":      {[]string{"3"}, 3, false},
+		"too big":  {[]string{"500"}, 100, false},
+		"zero":     {[]string{"0"}, 0, true},
+		"negative": {[]string{"-1"}, 0, true},
+		"garbage":  {[]string{"ten"}, 0, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			form := map[string][]string{}
+			if tc.value != nil {
+				form["limit"] = tc.value
+			}
+
+			got, err := intParam(form, "limit", 10, 100)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServeFormats(t *testing.T) {
+	c := newMemoryCache()
+	fs := http.FileServer(http.Dir("testdata"))
+	ms := httptest.NewServer(fs)
+	defer ms.Close()
+
+	s := httptest.NewServer(http.HandlerFunc(serveHcard(c)))
+	defer s.Close()
+
+	tests := map[string]struct {
+		format string
+		code   int
+		want   string
+	}{
+		"default": {"", http.StatusOK, wantHcard(ms.URL)},
+		"jf2": {"jf2", http.StatusOK, fmt.Sprintf(`{"name":"Евгений Кузнецов","nickname":"nekr0z",`+
+			`"photo":{"alt":"nekr0z","value":"%s/img/avatar.jpg"},"type":"card","url":"https://evgenykuznetsov.org"}`, ms.URL)},
+		"unsupported": {"xml", http.StatusBadRequest, "unsupported format\n"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(s.URL)
+			v := url.Values{}
+			v.Add("url", ms.URL)
+			if tc.format != "" {
+				v.Add("format", tc.format)
+			}
+			u.RawQuery = v.Encode()
+
+			res, err := http.Get(u.String())
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.code {
+				t.Fatalf("want status %d, got %d", tc.code, res.StatusCode)
+			}
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if string(b) != tc.want {
+				t.Fatalf("want %s, got %s", tc.want, b)
+			}
+		})
+	}
+}
+
+func TestServeVCard(t *testing.T) {
+	c := newMemoryCache()
+	fs := http.FileServer(http.Dir("testdata"))
+	ms := httptest.NewServer(fs)
+	defer ms.Close()
+
+	s := httptest.NewServer(http.HandlerFunc(serveHcard(c)))
+	defer s.Close()
+
+	tests := map[string]struct {
+		link        string
+		format      string
+		accept      string
+		code        int
+		contentType string
+		prefix      string
+	}{
+		"default":   {"/", "", "", http.StatusOK, "application/json", `{"source":`},
+		"vcf":       {"/", "vcf", "", http.StatusOK, "text/vcard", "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Евгений Кузнецов\r\n"},
+		"accept":    {"/", "", "text/html, text/vcard;q=0.9", http.StatusOK, "text/vcard", "BEGIN:VCARD\r\n"},
+		"refused":   {"/", "", "text/vcard;q=0", http.StatusOK, "application/json", `{"source":`},
+		"jcard":     {"/", "jcard", "", http.StatusOK, "application/vcard+json", `["vcard",[["version",{},"text","4.0"],["fn",{},"text","Евгений Кузнецов"]`},
+		"overrides": {"/", "jf2", "text/vcard", http.StatusOK, "application/json", `{"name":`},
+		"no card":   {"/404.html", "vcf", "", http.StatusNotFound, "text/plain; charset=utf-8", "no appropriate info at URL"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(s.URL)
+			v := url.Values{}
+			v.Add("url", ms.URL+tc.link)
+			if tc.format != "" {
+				v.Add("format", tc.format)
+			}
+			u.RawQuery = v.Encode()
+
+			req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.code {
+				t.Fatalf("want status %d, got %d", tc.code, res.StatusCode)
+			}
+			if ct := res.Header.Get("Content-Type"); ct != tc.contentType {
+				t.Fatalf("want content type %s, got %s", tc.contentType, ct)
+			}
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !strings.HasPrefix(string(b), tc.prefix) {
+				t.Fatalf("want %q to start with %q", b, tc.prefix)
+			}
+		})
+	}
+}
+
+func TestServeHevent(t *testing.T) {
+	c := newMemoryCache()
+	fs := http.FileServer(http.Dir("testdata"))
+	ms := httptest.NewServer(fs)
+	defer ms.Close()
+
+	s := httptest.NewServer(http.HandlerFunc(serveHevent(c)))
+	defer s.Close()
+
+	tests := map[string]struct {
+		format      string
+		accept      string
+		contentType string
+		contains    string
+	}{
+		"default": {"", "", "application/json", `"name":"Meetup","start":"2026-11-05 18:00+02:00","location":{"type":"text","name":"The park"}`},
+		"ics":     {"ics", "", "text/calendar", "\r\nDTSTART:20261105T160000Z\r\nSUMMARY:Meetup\r\nLOCATION:The park\r\n"},
+		"accept":  {"", "text/calendar", "text/calendar", "BEGIN:VCALENDAR\r\n"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(s.URL)
+			v := url.Values{}
+			v.Add("url", ms.URL+"/event.html")
+			if tc.format != "" {
+				v.Add("format", tc.format)
+			}
+			u.RawQuery = v.Encode()
+
+			req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if ct := res.Header.Get("Content-Type"); ct != tc.contentType {
+				t.Fatalf("want content type %s, got %s", tc.contentType, ct)
+			}
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !strings.Contains(string(b), tc.contains) {
+				t.Fatalf("want %q to contain %q", b, tc.contains)
+			}
+		})
+	}
+}
+
+func TestServeOembed(t *testing.T) {
+	c := newMemoryCache()
+	s := httptest.NewServer(http.HandlerFunc(serveOembed(c)))
+	defer s.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/video", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><head><link rel="alternate" type="application/json+oembed" href="/oembed?format=json"></head></html>`)
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"version":"1.0","type":"video","width":%s,"height":360,"html":"<iframe></iframe>"}`, r.FormValue("maxwidth"))
+	})
+	ms := httptest.NewServer(mux)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		maxWidth string
+		status   int
+		contains string
+	}{
+		"passed through": {"480", http.StatusOK, `"width":480,"height":360,"untrusted_html":"\u003ciframe\u003e\u003c/iframe\u003e"`},
+		"capped":         {"10000", http.StatusOK, `"width":4096,`},
+		"invalid":        {"wide", http.StatusBadRequest, "maxwidth must be a positive integer"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := url.Values{}
+			v.Add("url", ms.URL+"/video")
+			v.Add("maxwidth", tc.maxWidth)
+			res, err := http.Get(s.URL + "?" + v.Encode())
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.status {
+				t.Fatalf("want status %d, got %d", tc.status, res.StatusCode)
+			}
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !strings.Contains(string(b), tc.contains) {
+				t.Fatalf("want %q to contain %q", b, tc.contains)
+			}
+		})
+	}
+}
+
+func TestServeOembedProvider(t *testing.T) {
+	c := newMemoryCache()
+	fs := http.FileServer(http.Dir("testdata"))
+	ms := httptest.NewServer(fs)
+	defer ms.Close()
+
+	s := httptest.NewServer(http.HandlerFunc(serveOembedProvider(c)))
+	defer s.Close()
+
+	tests := map[string]struct {
+		page        string
+		format      string
+		maxWidth    string
+		status      int
+		contentType string
+		contains    string
+	}{
+		"json":        {"/", "json", "", http.StatusOK, "application/json", `{"type":"rich","version":"1.0","title":"DIMV",`},
+		"xml":         {"/", "xml", "", http.StatusOK, "text/xml", "<oembed><type>rich</type><version>1.0</version><title>DIMV</title>"},
+		"maxwidth":    {"/", "", "320", http.StatusOK, "application/json", `"width":320,"height":150}`},
+		"unsupported": {"/", "yaml", "", http.StatusNotImplemented, "text/plain; charset=utf-8", "unsupported format"},
+		"no page":     {"/nonexistent", "xml", "", http.StatusNotFound, "text/plain; charset=utf-8", "no appropriate info at URL"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := url.Values{}
+			v.Add("url", ms.URL+tc.page)
+			if tc.format != "" {
+				v.Add("format", tc.format)
+			}
+			if tc.maxWidth != "" {
+				v.Add("maxwidth", tc.maxWidth)
+			}
+			res, err := http.Get(s.URL + "?" + v.Encode())
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.status {
+				t.Fatalf("want status %d, got %d", tc.status, res.StatusCode)
+			}
+			if ct := res.Header.Get("Content-Type"); ct != tc.contentType {
+				t.Fatalf("want content type %s, got %s", tc.contentType, ct)
+			}
+
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !strings.Contains(string(b), tc.contains) {
+				t.Fatalf("want %q to contain %q", b, tc.contains)
+			}
+		})
+	}
+}
+
+func TestServeRelMe(t *testing.T) {
+	c := newMemoryCache()
+	s := httptest.NewServer(http.HandlerFunc(serveRelMe(c)))
+	defer s.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><body><a rel="me" href="/b">b</a></body></html>`)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><body><a rel="me" href="/a">a</a></body></html>`)
+	})
+	ms := httptest.NewServer(mux)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		from, to string
+		code     int
+	}{
+		"verified": {ms.URL + "/a", ms.URL + "/b", http.StatusOK},
+		"no to":    {ms.URL + "/a", "", http.StatusBadRequest},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(s.URL)
+			v := url.Values{}
+			v.Add("from", tc.from)
+			v.Add("to", tc.to)
+			u.RawQuery = v.Encode()
+
+			res, err := http.Get(u.String())
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.code {
+				t.Fatalf("want status %d, got %d", tc.code, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRelMeKey(t *testing.T) {
+	if relMeKey("https://a.example/", "https://b.example/") != relMeKey("HTTPS://A.example", "https://b.example/#me") {
+		t.Fatal("equivalent pairs should share the key")
+	}
+	if relMeKey("https://a.example/x y", "https://b.example/") == relMeKey("https://a.example/x", "y https://b.example/") {
+		t.Fatal("different pairs should not share the key")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		same bool
+	}{
+		"empty path":     {"https://example.com", "https://example.com/", true},
+		"case":           {"HTTPS://Example.com/", "https://example.com/", true},
+		"default port":   {"https://example.com:443/", "https://example.com/", true},
+		"fragment":       {"https://example.com/#top", "https://example.com/", true},
+		"http and https": {"http://example.com/", "https://example.com/", false},
+		"trailing slash": {"https://example.com/a/", "https://example.com/a", false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := cacheKey("hcard", tc.a) == cacheKey("hcard", tc.b); got != tc.same {
+				t.Fatalf("want same key %v for %s and %s, got %v", tc.same, tc.a, tc.b, got)
+			}
+		})
+	}
+}